@@ -0,0 +1,150 @@
+package query_builder
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// columns filled by InsertQueryBuilder and UpdateQueryBuilder. empty column is not filled.
+// insert fills all columns, update fills UpdatedAt and UpdatedBy.
+// columns which are already set by Column or Model are kept as it is.
+type AuditColumns struct {
+	CreatedAt string
+	UpdatedAt string
+	CreatedBy string
+	UpdatedBy string
+	// timestamp is CURRENT_TIMESTAMP when Now is nil, otherwise Now() is bound. ex. fixed clock in tests
+	Now func() time.Time
+}
+
+var (
+	defaultAuditColumns      *AuditColumns
+	defaultAuditColumnsMutex sync.RWMutex
+)
+
+type actorContextKey struct{}
+
+type auditTarget struct {
+	column    string
+	timestamp bool
+}
+
+// applied to builders which call DefaultAudit, so positional args of other builders are not shifted. nil disables.
+func SetDefaultAuditColumns(columns *AuditColumns) {
+	defaultAuditColumnsMutex.Lock()
+	defer defaultAuditColumnsMutex.Unlock()
+	defaultAuditColumns = columns
+}
+
+func getDefaultAuditColumns() *AuditColumns {
+	defaultAuditColumnsMutex.RLock()
+	defer defaultAuditColumnsMutex.RUnlock()
+	return defaultAuditColumns
+}
+
+// actor is bound to CreatedBy and UpdatedBy. ex. user id of request
+func ContextWithActor(ctx context.Context, actor interface{}) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) (interface{}, bool) {
+	actor := ctx.Value(actorContextKey{})
+	return actor, actor != nil
+}
+
+func (builder *queryBuilder) setAudit(columns *AuditColumns) *queryBuilder {
+	copied := builder.copy()
+	copied.audit = columns
+	return copied
+}
+
+func (builder *queryBuilder) setDefaultAudit() *queryBuilder {
+	copied := builder.copy()
+	copied.defaultAudit = true
+	return copied
+}
+
+// Audit columns, or default columns when DefaultAudit is called
+func (builder *queryBuilder) getAuditColumns() *AuditColumns {
+	if builder.audit != nil {
+		return builder.audit
+	}
+	if builder.defaultAudit {
+		return getDefaultAuditColumns()
+	}
+	return nil
+}
+
+// Now is called once, so query and args of one execution have same time
+func (builder *queryBuilder) freezeClock() *queryBuilder {
+	columns := builder.getAuditColumns()
+	if columns == nil || columns.Now == nil {
+		return builder
+	}
+
+	now := columns.Now()
+	frozen := *columns
+	frozen.Now = func() time.Time { return now }
+
+	copied := builder.copy()
+	copied.audit = &frozen
+	return copied
+}
+
+func (builder *queryBuilder) setContext(ctx context.Context) *queryBuilder {
+	copied := builder.copy()
+	copied.actor, _ = ActorFromContext(ctx)
+	return copied
+}
+
+// add audit columns with CURRENT_TIMESTAMP expression or bound value
+func (builder *queryBuilder) audited(usage int) *queryBuilder {
+	columns := builder.getAuditColumns()
+	if columns == nil {
+		return builder
+	}
+
+	var timestamp interface{}
+	if columns.Now != nil {
+		timestamp = columns.Now()
+	}
+
+	targets := []auditTarget{{columns.UpdatedAt, true}, {columns.UpdatedBy, false}}
+	if usage == modelInsert {
+		targets = append([]auditTarget{{columns.CreatedAt, true}, {columns.CreatedBy, false}}, targets...)
+	}
+
+	copied := builder.copy()
+	copied.columns = append(make([]string, 0, len(builder.columns)+len(targets)), builder.columns...)
+	copied.expressions = copyStringMap(builder.expressions)
+	copied.values = builder.getNamedArgs(nil)
+
+	for _, target := range targets {
+		if target.column == "" || containsString(copied.columns, target.column) {
+			continue
+		}
+
+		switch {
+		case target.timestamp && timestamp == nil:
+			copied.expressions[target.column] = "CURRENT_TIMESTAMP"
+		case target.timestamp:
+			copied.values[target.column] = timestamp
+		case builder.actor == nil:
+			// no actor in context
+			continue
+		default:
+			copied.values[target.column] = builder.actor
+		}
+		copied.columns = append(copied.columns, target.column)
+	}
+	return copied
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	copied := make(map[string]string, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
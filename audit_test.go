@@ -0,0 +1,143 @@
+package query_builder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_InsertQueryBuilder_Audit(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	columns := &AuditColumns{
+		CreatedAt: "created_at",
+		UpdatedAt: "updated_at",
+		CreatedBy: "created_by",
+		UpdatedBy: "updated_by",
+		Now:       func() time.Time { return now },
+	}
+	ctx := ContextWithActor(context.Background(), "admin")
+
+	builder := NewInsertQueryBuilder().
+		Audit(columns).
+		Context(ctx).
+		Table("users").
+		Column("name", "updated_by")
+
+	testCommonFunc(
+		t,
+		"INSERT INTO users(name, updated_by, created_at, created_by, updated_at) VALUES(?, ?, ?, ?, ?);",
+		builder.Build(),
+		true,
+	)
+
+	args, err := builder.Args(map[string]interface{}{"name": "trewanek", "updated_by": "system"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{"trewanek", "system", now, "admin", now}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+
+	testCommonFunc(
+		t,
+		"INSERT INTO users(name, created_at, updated_at) VALUES(:name, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);",
+		NewInsertQueryBuilder().
+			Placeholder(Named).
+			Audit(&AuditColumns{CreatedAt: "created_at", UpdatedAt: "updated_at", CreatedBy: "created_by"}).
+			Table("users").
+			Column("name").
+			Build(),
+		false,
+	)
+}
+
+func Test_UpdateQueryBuilder_Audit(t *testing.T) {
+	SetDefaultAuditColumns(&AuditColumns{
+		CreatedAt: "created_at",
+		UpdatedAt: "updated_at",
+		CreatedBy: "created_by",
+		UpdatedBy: "updated_by",
+	})
+	defer SetDefaultAuditColumns(nil)
+
+	builder := NewUpdateQueryBuilder().
+		DefaultAudit().
+		Context(ContextWithActor(context.Background(), 10)).
+		Table("users").
+		Column("name").
+		Where("user_id", Equal)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = $1, updated_at = CURRENT_TIMESTAMP, updated_by = $2 WHERE user_id = $3;",
+		builder.Placeholder(DollarNumber).Build(),
+		false,
+	)
+
+	args, err := builder.Args(map[string]interface{}{"name": "trewanek", "user_id": "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{"trewanek", 10, "u1"}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+
+	// default columns are not added without DefaultAudit, so hand-passed args are not shifted
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ? WHERE user_id = ?;",
+		NewUpdateQueryBuilder().
+			Context(ContextWithActor(context.Background(), 10)).
+			Table("users").
+			Column("name").
+			Where("user_id", Equal).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ?;",
+		NewUpdateQueryBuilder().
+			DefaultAudit().
+			Audit(&AuditColumns{}).
+			Table("users").
+			Column("name").
+			Build(),
+		true,
+	)
+}
+
+func Test_InsertQueryBuilder_AuditClock(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	calls := 0
+	builder := NewInsertQueryBuilder().
+		Audit(&AuditColumns{
+			CreatedAt: "created_at",
+			UpdatedAt: "updated_at",
+			Now: func() time.Time {
+				calls++
+				return now.Add(time.Duration(calls) * time.Second)
+			},
+		}).
+		Table("users").
+		Column("name")
+
+	args, err := builder.Args(map[string]interface{}{"name": "trewanek"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// clock is called once per Args
+	expected := now.Add(time.Second)
+	expectedArgs := []interface{}{"trewanek", expected, expected}
+	if calls != 1 || !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v, calls: %d", expectedArgs, args, calls)
+		t.Fail()
+	}
+}
@@ -0,0 +1,82 @@
+package query_builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+type CaseExpression struct {
+	whens      []map[string]interface{}
+	elseResult string
+	elseRaw    bool
+}
+
+// CASE WHEN {condition} THEN {result} ... ELSE {result} END
+// result is rendered as it is. ex. 'active', 1, users.name
+// Strict mode accepts only identifier or literal result, expression needs WhenRaw and ElseRaw.
+// placeholders in conditions are rendered by the builder that uses this expression.
+func Case() *CaseExpression {
+	return &CaseExpression{}
+}
+
+func (expression *CaseExpression) copy() *CaseExpression {
+	return &CaseExpression{
+		whens:      expression.whens,
+		elseResult: expression.elseResult,
+		elseRaw:    expression.elseRaw,
+	}
+}
+
+// nil or empty condition panics, because WHEN without condition is invalid
+func (expression *CaseExpression) When(condition *Condition, result string) *CaseExpression {
+	return expression.when(condition, result, false)
+}
+
+// ex. WhenRaw(Cond("status", Equal), Raw("UPPER(name)")) => WHEN status = ? THEN UPPER(name)
+func (expression *CaseExpression) WhenRaw(condition *Condition, result RawExpr) *CaseExpression {
+	return expression.when(condition, result.expression, true)
+}
+
+func (expression *CaseExpression) when(condition *Condition, result string, raw bool) *CaseExpression {
+	if condition == nil || len(condition.conditions) == 0 {
+		panic(CaseConditionRequiredErr)
+	}
+
+	copied := expression.copy()
+	copied.whens = append(append(make([]map[string]interface{}, 0, len(expression.whens)+1), expression.whens...), map[string]interface{}{
+		"conditions": condition.conditions,
+		"result":     result,
+		"raw":        raw,
+	})
+	return copied
+}
+
+func (expression *CaseExpression) Else(result string) *CaseExpression {
+	copied := expression.copy()
+	copied.elseResult = result
+	copied.elseRaw = false
+	return copied
+}
+
+func (expression *CaseExpression) ElseRaw(result RawExpr) *CaseExpression {
+	copied := expression.copy()
+	copied.elseResult = result.expression
+	copied.elseRaw = true
+	return copied
+}
+
+func (expression *CaseExpression) build(builder *queryBuilder) string {
+	if len(expression.whens) == 0 {
+		panic(CaseWhenRequiredErr)
+	}
+
+	paragraphs := []string{"CASE"}
+	for _, when := range expression.whens {
+		paragraphs = append(paragraphs, builder.getConditionParagraphs("WHEN", when["conditions"].([]map[string]string))...)
+		paragraphs = append(paragraphs, fmt.Sprintf("THEN %s", when["result"]))
+	}
+	if expression.elseResult != "" {
+		paragraphs = append(paragraphs, fmt.Sprintf("ELSE %s", expression.elseResult))
+	}
+	return strings.Join(append(paragraphs, "END"), " ")
+}
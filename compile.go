@@ -0,0 +1,170 @@
+package query_builder
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	CompileArgErr   = fmt.Errorf("compile arg should be struct or map with string key")
+	BindNotFoundErr = fmt.Errorf("bind value is not found")
+)
+
+// Compile converts Named query into placeholderType query and args in placeholder order.
+// arg is map or struct. struct value is looked up by db tag and search tag(same value as WhereMultiByStruct keeps).
+// :{bind}1..N of IN list and BETWEEN are expanded from slice of {bind}.
+// quoted strings and :: cast are kept as it is. Named keeps query as it is.
+// ex. Compile("SELECT users.* FROM users WHERE user_id IN (:user_ids1, :user_ids2);", map[string]interface{}{"user_ids": []int{1, 2}}, DollarNumber)
+// => SELECT users.* FROM users WHERE user_id IN ($1, $2);, [1 2]
+func Compile(query string, arg interface{}, placeholderType int) (string, []interface{}, error) {
+	values, err := getNamedValues(arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	compiled, args, _, err := compile(query, values, placeholderType)
+	return compiled, args, err
+}
+
+// names are binds of args. ex. user_ids1, user_ids2
+func compile(query string, values map[string]interface{}, placeholderType int) (string, []interface{}, []string, error) {
+	var compiled strings.Builder
+	args := make([]interface{}, 0)
+	names := make([]string, 0)
+	runes := []rune(query)
+	var quote rune
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+			compiled.WriteRune(r)
+			continue
+		}
+
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == ':' && i+1 < len(runes) && runes[i+1] == ':':
+			// PostgreSQL cast. ex. created::date
+			compiled.WriteString("::")
+			i++
+			continue
+		case r == ':' && i+1 < len(runes) && isBindStartRune(runes[i+1]):
+			end := getBindEnd(runes, i+1)
+			name := string(runes[i+1 : end])
+			value, ok := lookupNamedValue(values, name)
+			if !ok {
+				return "", nil, nil, fmt.Errorf("%w: %s", BindNotFoundErr, name)
+			}
+			args = append(args, value)
+			names = append(names, name)
+
+			switch placeholderType {
+			case Named:
+				compiled.WriteString(":" + name)
+			case DollarNumber:
+				compiled.WriteString("$" + strconv.Itoa(len(args)))
+			default:
+				compiled.WriteString("?")
+			}
+			i = end - 1
+			continue
+		}
+		compiled.WriteRune(r)
+	}
+	return compiled.String(), args, names, nil
+}
+
+func isBindStartRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isBindRune(r rune) bool {
+	return isBindStartRune(r) || (r >= '0' && r <= '9')
+}
+
+// bind of qualified column contains dot. ex. :users.user_id
+func getBindEnd(runes []rune, start int) int {
+	end := start
+	for end < len(runes) {
+		if isBindRune(runes[end]) {
+			end++
+			continue
+		}
+		if runes[end] == '.' && end+1 < len(runes) && isBindStartRune(runes[end+1]) {
+			end++
+			continue
+		}
+		break
+	}
+	return end
+}
+
+// {bind}N => N-th element of {bind}
+func lookupNamedValue(values map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := values[name]; ok {
+		return value, true
+	}
+
+	base := strings.TrimRight(name, "0123456789")
+	index, err := strconv.Atoi(name[len(base):])
+	if err != nil || index == 0 {
+		return nil, false
+	}
+
+	value, ok := values[base]
+	if !ok || value == nil {
+		return nil, false
+	}
+
+	list := reflect.Indirect(reflect.ValueOf(value))
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array || list.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	if index > list.Len() {
+		return nil, false
+	}
+	return reflect.Indirect(list.Index(index - 1)).Interface(), true
+}
+
+func getNamedValues(arg interface{}) (map[string]interface{}, error) {
+	if arg == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(arg))
+	values := make(map[string]interface{})
+
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		iter := v.MapRange()
+		for iter.Next() {
+			values[iter.Key().String()] = iter.Value().Interface()
+		}
+	case v.Kind() == reflect.Struct:
+		for _, field := range getModelFields(v.Type()) {
+			fieldValue, found := fieldByIndex(v, field.index)
+			if !found || !fieldValue.CanInterface() {
+				continue
+			}
+			if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
+				values[field.column] = nil
+				continue
+			}
+			values[field.column] = reflect.Indirect(fieldValue).Interface()
+		}
+
+		_, namedArgs := newQueryBuilder().buildBindArgs(SearchTag, v.Interface())
+		for name, value := range namedArgs {
+			values[name] = value
+		}
+	default:
+		return nil, CompileArgErr
+	}
+	return values, nil
+}
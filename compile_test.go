@@ -0,0 +1,136 @@
+package query_builder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_Compile(t *testing.T) {
+	type SearchUsersParameter struct {
+		UserIDs    []int   `db:"user_id" search:"user_ids" operator:"in"`
+		AgeRange   []int   `db:"age" search:"age_range" operator:"between"`
+		NamePrefix *string `db:"name" search:"name_prefix" operator:"prefix"`
+	}
+
+	prefix := "tre"
+	searchParam := SearchUsersParameter{UserIDs: []int{1, 2, 3}, AgeRange: []int{20, 30}, NamePrefix: &prefix}
+	query := NewSelectQueryBuilder().
+		Placeholder(Named).
+		Table("users").
+		WhereMultiByStruct(searchParam).
+		Build()
+
+	tests := []struct {
+		name            string
+		query           string
+		arg             interface{}
+		placeholderType int
+		expected        string
+		expectedArgs    []interface{}
+	}{
+		{
+			name:            "struct to dollar number",
+			query:           query,
+			arg:             searchParam,
+			placeholderType: DollarNumber,
+			expected:        "SELECT users.* FROM users WHERE user_id IN ($1, $2, $3) AND age BETWEEN $4 AND $5 AND name LIKE $6 ESCAPE '\\\\';",
+			expectedArgs:    []interface{}{1, 2, 3, 20, 30, "tre%"},
+		},
+		{
+			name:  "map to question",
+			query: query,
+			arg: map[string]interface{}{
+				"user_ids":    []int{1, 2, 3},
+				"age_range":   [2]int{20, 30},
+				"name_prefix": "tre%",
+			},
+			placeholderType: Question,
+			expected:        "SELECT users.* FROM users WHERE user_id IN (?, ?, ?) AND age BETWEEN ? AND ? AND name LIKE ? ESCAPE '\\\\';",
+			expectedArgs:    []interface{}{1, 2, 3, 20, 30, "tre%"},
+		},
+		{
+			name:            "quoted string and cast",
+			query:           "SELECT ':not_bind', created::date FROM users WHERE name = 'it''s :x' AND users.user_id = :users.user_id AND age >= :age1;",
+			arg:             map[string]interface{}{"users.user_id": "u1", "age1": 20},
+			placeholderType: DollarNumber,
+			expected:        "SELECT ':not_bind', created::date FROM users WHERE name = 'it''s :x' AND users.user_id = $1 AND age >= $2;",
+			expectedArgs:    []interface{}{"u1", 20},
+		},
+		{
+			name:            "named keeps query",
+			query:           "UPDATE users SET name = :name, age = :age WHERE user_id = :user_id;",
+			arg:             &User{UserID: "u1", Name: "trewanek", Age: 20},
+			placeholderType: Named,
+			expected:        "UPDATE users SET name = :name, age = :age WHERE user_id = :user_id;",
+			expectedArgs:    []interface{}{"trewanek", 20, "u1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, args, err := Compile(tt.query, tt.arg, tt.placeholderType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tt.expected {
+				t.Logf("\nexpected: %s\nactual  : %s", tt.expected, actual)
+				t.Fail()
+			}
+			if !reflect.DeepEqual(tt.expectedArgs, args) {
+				t.Logf("expected: %v, actual: %v", tt.expectedArgs, args)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func Test_Compile_WithStructArgs(t *testing.T) {
+	type SearchUsersParameter struct {
+		UserIDs    []int   `db:"user_id" search:"user_ids" operator:"in"`
+		NamePrefix *string `db:"name" search:"name_prefix" operator:"prefix"`
+	}
+
+	prefix := "tre_"
+	searchParam := SearchUsersParameter{UserIDs: []int{1, 2}, NamePrefix: &prefix}
+	_, args, err := Compile(
+		NewSelectQueryBuilder().Placeholder(Named).Table("users").WhereMultiByStruct(searchParam).Build(),
+		searchParam,
+		Question,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedArgs, err := NewSelectQueryBuilder().Table("users").WhereMultiByStruct(searchParam).Args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+}
+
+func Test_Compile_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		arg      interface{}
+		expected error
+	}{
+		{"missing bind", "SELECT users.* FROM users WHERE name = :name;", map[string]interface{}{}, BindNotFoundErr},
+		{"list out of range", "SELECT users.* FROM users WHERE user_id IN (:ids1, :ids2);", map[string]interface{}{"ids": []int{1}}, BindNotFoundErr},
+		{"not list", "SELECT users.* FROM users WHERE user_id = :id1;", map[string]interface{}{"id": 1}, BindNotFoundErr},
+		{"invalid arg", "SELECT users.* FROM users;", []string{"x"}, CompileArgErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Compile(tt.query, tt.arg, Question); !errors.Is(err, tt.expected) {
+				t.Logf("expected: %v, actual: %v", tt.expected, err)
+				t.Fail()
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package query_builder
+
+type Condition struct {
+	conditions []map[string]string
+}
+
+// condition for WhereCondition and CaseExpression.When. column, operator and bind are same as Where.
+// ex. Cond("status", Equal).Or("status", IsNull) => status = ? OR status IS NULL
+func Cond(column, operator string, bind ...string) *Condition {
+	builder := newQueryBuilder().where(column, operator, bind...)
+	return &Condition{builder.whereConditions}
+}
+
+// ex. CondRaw(Raw("LOWER(name)"), Equal, "name") => LOWER(name) = ?
+func CondRaw(expression RawExpr, operator string, bind ...string) *Condition {
+	builder := newQueryBuilder().whereRaw("AND", expression, operator, bind...)
+	return &Condition{builder.whereConditions}
+}
+
+// ex. CondIn("user_id", 3) => user_id IN (?, ?, ?)
+func CondIn(column string, listLength int, bind ...string) *Condition {
+	builder := newQueryBuilder().whereIn(column, listLength, bind...)
+	return &Condition{builder.whereConditions}
+}
+
+// value is LikePattern. ex. CondLikePattern("name", "name_prefix") => name LIKE ? ESCAPE '\'
+func CondLikePattern(column string, bind ...string) *Condition {
+	builder := newQueryBuilder().where(column, Like, bind...)
+	builder.whereConditions[0]["escape"] = "true"
+	return &Condition{builder.whereConditions}
+}
+
+func CondNotIn(column string, listLength int, bind ...string) *Condition {
+	builder := newQueryBuilder().whereNotIn(column, listLength, bind...)
+	return &Condition{builder.whereConditions}
+}
+
+// member conditions are parenthesized.
+// ex. AllOf(Cond("age", GraterThanEqual), AnyOf(Cond("name", Like), Cond("email", Like)))
+// => age >= ? AND (name LIKE ? OR email LIKE ?)
+func AllOf(conditions ...*Condition) *Condition {
+	return combineConditions("AND", conditions)
+}
+
+func AnyOf(conditions ...*Condition) *Condition {
+	return combineConditions("OR", conditions)
+}
+
+func (condition *Condition) And(column, operator string, bind ...string) *Condition {
+	builder := condition.builder().where(column, operator, bind...)
+	return &Condition{builder.whereConditions}
+}
+
+func (condition *Condition) Or(column, operator string, bind ...string) *Condition {
+	builder := condition.builder().or(column, operator, bind...)
+	return &Condition{builder.whereConditions}
+}
+
+// conditions are copied, so appending does not share backing array with other conditions of same base
+func (condition *Condition) builder() *queryBuilder {
+	builder := newQueryBuilder()
+	builder.whereConditions = append(make([]map[string]string, 0, len(condition.conditions)+1), condition.conditions...)
+	return builder
+}
+
+// returns copied conditions, because logical and parentheses are overwritten on combination
+func (condition *Condition) copyConditions() []map[string]string {
+	copied := make([]map[string]string, 0, len(condition.conditions))
+	for _, c := range condition.conditions {
+		m := make(map[string]string, len(c))
+		for key, value := range c {
+			m[key] = value
+		}
+		copied = append(copied, m)
+	}
+	return copied
+}
+
+func combineConditions(logical string, conditions []*Condition) *Condition {
+	members := make([][]map[string]string, 0, len(conditions))
+	for _, condition := range conditions {
+		if condition == nil || len(condition.conditions) == 0 {
+			continue
+		}
+		members = append(members, wrapBindInfos(condition.copyConditions()))
+	}
+	return &Condition{joinBindInfos(members, logical)}
+}
+
+// appends condition as a parenthesized group
+func (builder *queryBuilder) whereCondition(logical string, condition *Condition) *queryBuilder {
+	copied := builder.copy()
+	if condition == nil || len(condition.conditions) == 0 {
+		return copied
+	}
+
+	conditions := wrapBindInfos(condition.copyConditions())
+	conditions[0]["logical"] = logical
+	copied.whereConditions = append(append(make([]map[string]string, 0, len(builder.whereConditions)+len(conditions)), builder.whereConditions...), conditions...)
+	return copied
+}
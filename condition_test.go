@@ -0,0 +1,65 @@
+package query_builder
+
+import "testing"
+
+func Test_SelectQueryBuilder_WhereCondition(t *testing.T) {
+	keyword := AnyOf(Cond("name", Like), Cond("email", Like), Cond("phone", Like))
+	condition := AllOf(
+		Cond("age", GraterThanEqual),
+		keyword,
+		CondIn("user_id", 2),
+		Cond("deleted", IsNull).Or("deleted", Equal),
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users "+
+			"WHERE status = $1 "+
+			"AND (age >= $2 AND (name LIKE $3 OR email LIKE $4 OR phone LIKE $5) AND user_id IN ($6, $7) AND (deleted IS NULL OR deleted = $8)) "+
+			"OR sex = $9;",
+		NewSelectQueryBuilder().
+			Placeholder(DollarNumber).
+			Table("users").
+			Where("status", Equal).
+			WhereCondition(condition).
+			OrCondition(Cond("sex", Equal)).
+			Build(),
+		false,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE (name LIKE ? OR email LIKE ? OR phone LIKE ?);",
+		NewSelectQueryBuilder().
+			Table("users").
+			WhereCondition(keyword).
+			Build(),
+		true,
+	)
+
+	// base is not changed by And of derived conditions
+	base := Cond("a", Equal).And("b", Equal).And("c", Equal)
+	d, e := base.And("d", Equal), base.And("e", Equal)
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE (a = ? AND b = ? AND c = ? AND d = ?) AND (a = ? AND b = ? AND c = ? AND e = ?) AND (a = ? AND b = ? AND c = ?);",
+		NewSelectQueryBuilder().
+			Table("users").
+			WhereCondition(d).
+			WhereCondition(e).
+			WhereCondition(base).
+			Build(),
+		true,
+	)
+
+	// combination does not change members
+	testCommonFunc(
+		t,
+		"SELECT CASE WHEN name LIKE ? OR email LIKE ? OR phone LIKE ? THEN 1 END AS matched FROM users;",
+		NewSelectQueryBuilder().
+			Table("users").
+			CaseColumn(Case().When(keyword, "1"), "matched").
+			Build(),
+		true,
+	)
+}
@@ -11,8 +11,9 @@ const (
 	NotLike         = "NOT LIKE"    // tag.operator.not-like
 	IsNull          = "IS NULL"     // tag.operator.is-null
 	IsNotNull       = "IS NOT NULL" // tag.operator.not-null
-	In              = "IN"
-	NotIn           = "NOT IN"
+	In              = "IN"          // tag.operator.in
+	NotIn           = "NOT IN"      // tag.operator.not-in
+	Between         = "BETWEEN"     // tag.operator.between
 )
 
 const (
@@ -21,6 +22,12 @@ const (
 	Named
 )
 
+const (
+	MySQL = iota
+	PostgreSQL
+	SQLServer
+)
+
 const (
 	LeftJoin  = "LEFT JOIN"
 	RightJoin = "RIGHT JOIN"
@@ -32,9 +39,37 @@ const (
 	Desc = "DESC"
 )
 
+const (
+	UnboundedPreceding = "UNBOUNDED PRECEDING"
+	UnboundedFollowing = "UNBOUNDED FOLLOWING"
+	CurrentRow         = "CURRENT ROW"
+)
+
+const (
+	LockForUpdate      = "FOR UPDATE"
+	LockForNoKeyUpdate = "FOR NO KEY UPDATE"
+	LockForShare       = "FOR SHARE"
+	LockNoWait         = "NOWAIT"
+	LockSkipLocked     = "SKIP LOCKED"
+)
+
 const (
 	DBTag       = "db"
 	TableTag    = "table"
 	SearchTag   = "search"
 	OperatorTag = "operator"
+	GroupTag    = "group"
+	LogicTag    = "logic"
+	PrefixTag   = "prefix"
+)
+
+// db tag options. ex. `db:"id,pk,autoincr"`, `db:"-"` is ignored
+const (
+	PrimaryKeyOption    = "pk"
+	AutoIncrementOption = "autoincr"
+	ReadOnlyOption      = "readonly"
+	OmitEmptyOption     = "omitempty"
+	DefaultOption       = "default"
+	VersionOption       = "version"
+	AlwaysOption        = "always"
 )
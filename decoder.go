@@ -0,0 +1,274 @@
+package query_builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	DecodeTargetErr = fmt.Errorf("decode target should be pointer of struct")
+	JSONValueErr    = fmt.Errorf("value should be scalar or array of scalars")
+	timeType        = reflect.TypeOf(time.Time{})
+	timeLayouts     = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+)
+
+type DecodeError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (err *DecodeError) Error() string {
+	return fmt.Sprintf("%s: can not decode %q. %v", err.Field, err.Value, err.Err)
+}
+
+func (err *DecodeError) Unwrap() error {
+	return err.Err
+}
+
+type DecodeErrors []*DecodeError
+
+func (errs DecodeErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, ", ")
+}
+
+// fill struct tagged for WhereMultiByStruct by search tag name.
+// slice field accepts repeated key or comma separated value. ex. ?user_ids=1&user_ids=2, ?user_ids=1,2
+// empty value is skipped. errors of all fields are returned as DecodeErrors.
+func DecodeSearchValues(values url.Values, dst interface{}) error {
+	return decodeSearch(values, nil, dst, true)
+}
+
+// elements of JSON array are not split by comma.
+// invalid is search tag name => raw JSON of object or nested array, which is reported when field uses it
+func decodeSearch(values url.Values, invalid map[string]string, dst interface{}, commaSeparated bool) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return DecodeTargetErr
+	}
+
+	var errs DecodeErrors
+	decodeSearchStruct(values, invalid, v.Elem(), commaSeparated, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// array is slice as it is, so element including comma is not split. ex. {"names": ["Smith, John"]}
+// ex. {"user_ids": [1, 2], "name_prefix": "tre", "created_from": "2020-01-01"}
+// object and array of objects or arrays are DecodeError of JSONValueErr. keys of no field are ignored
+func DecodeSearchJSON(data []byte, dst interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	object := make(map[string]interface{})
+	if err := decoder.Decode(&object); err != nil {
+		return err
+	}
+
+	values := make(url.Values, len(object))
+	invalid := make(map[string]string)
+	for key, value := range object {
+		list, ok := value.([]interface{})
+		if !ok {
+			list = []interface{}{value}
+		}
+		if !isJSONScalars(list) {
+			raw, _ := json.Marshal(value)
+			invalid[key] = string(raw)
+			continue
+		}
+		for _, element := range list {
+			if element == nil {
+				continue
+			}
+			values.Add(key, fmt.Sprint(element))
+		}
+	}
+	return decodeSearch(values, invalid, dst, false)
+}
+
+func isJSONScalars(list []interface{}) bool {
+	for _, element := range list {
+		switch element.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+func decodeSearchStruct(values url.Values, invalid map[string]string, v reflect.Value, commaSeparated bool, errs *DecodeErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		// nested struct for group
+		if field.Tag.Get(DBTag) == "" && indirectType(field.Type).Kind() == reflect.Struct && indirectType(field.Type) != timeType {
+			nested := reflect.New(indirectType(field.Type)).Elem()
+			before := len(*errs)
+			if decodeSearchStruct(values, invalid, nested, commaSeparated, errs); !nested.IsZero() || len(*errs) > before {
+				setDecoded(fieldValue, nested)
+			}
+			continue
+		}
+
+		name := field.Tag.Get(SearchTag)
+		if raw, ok := invalid[name]; ok && name != "" {
+			*errs = append(*errs, &DecodeError{Field: name, Value: raw, Err: JSONValueErr})
+			continue
+		}
+		kind := indirectType(field.Type).Kind()
+		raw := splitSearchValues(values[name], commaSeparated && (kind == reflect.Slice || kind == reflect.Array))
+		if name == "" || len(raw) == 0 {
+			continue
+		}
+
+		// between needs from and to, otherwise the condition would be skipped silently
+		if getOperatorFromTag(field.Tag.Get(OperatorTag)) == Between && len(raw) != 2 {
+			*errs = append(*errs, &DecodeError{Field: name, Value: strings.Join(raw, ","), Err: BetweenValueErr})
+			continue
+		}
+
+		decoded, err := decodeSearchValue(field.Type, raw)
+		if err != nil {
+			*errs = append(*errs, &DecodeError{Field: name, Value: strings.Join(raw, ","), Err: err})
+			continue
+		}
+		fieldValue.Set(decoded)
+	}
+}
+
+func splitSearchValues(values []string, commaSeparated bool) []string {
+	split := make([]string, 0, len(values))
+	for _, value := range values {
+		elements := []string{value}
+		if commaSeparated {
+			elements = strings.Split(value, ",")
+		}
+		for _, element := range elements {
+			if element = strings.TrimSpace(element); element != "" {
+				split = append(split, element)
+			}
+		}
+	}
+	return split
+}
+
+func decodeSearchValue(t reflect.Type, raw []string) (reflect.Value, error) {
+	switch {
+	case t.Kind() == reflect.Ptr:
+		elem, err := decodeSearchValue(t.Elem(), raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case t.Kind() == reflect.Slice:
+		slice := reflect.MakeSlice(t, 0, len(raw))
+		for _, value := range raw {
+			elem, err := decodeSearchValue(t.Elem(), []string{value})
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		return slice, nil
+	case t.Kind() == reflect.Array:
+		if len(raw) != t.Len() {
+			return reflect.Value{}, fmt.Errorf("%d values are required", t.Len())
+		}
+		array := reflect.New(t).Elem()
+		for index, value := range raw {
+			elem, err := decodeSearchValue(t.Elem(), []string{value})
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			array.Index(index).Set(elem)
+		}
+		return array, nil
+	}
+
+	if len(raw) > 1 {
+		return reflect.Value{}, fmt.Errorf("multiple values are not allowed")
+	}
+	return decodeScalar(t, raw[0])
+}
+
+func decodeScalar(t reflect.Type, raw string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if t == timeType {
+		for _, layout := range timeLayouts {
+			if parsed, err := time.Parse(layout, raw); err == nil {
+				v.Set(reflect.ValueOf(parsed))
+				return v, nil
+			}
+		}
+		return reflect.Value{}, fmt.Errorf("time format should be RFC3339 or 2006-01-02")
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.SetFloat(parsed)
+	default:
+		return reflect.Value{}, fmt.Errorf("%s is not supported", t)
+	}
+	return v, nil
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+func setDecoded(fieldValue, decoded reflect.Value) {
+	if fieldValue.Kind() == reflect.Ptr {
+		ptr := reflect.New(decoded.Type())
+		ptr.Elem().Set(decoded)
+		fieldValue.Set(ptr)
+		return
+	}
+	fieldValue.Set(decoded)
+}
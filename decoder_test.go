@@ -0,0 +1,230 @@
+package query_builder
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type decoderTestParameter struct {
+	UserIDs  []int      `db:"user_id" search:"user_ids" operator:"in"`
+	AgeRange *[2]int    `db:"age" search:"age_range" operator:"between"`
+	Name     *string    `db:"name" search:"name" operator:"contains"`
+	Score    float64    `db:"score" search:"score" operator:"gte"`
+	Deleted  *bool      `db:"deleted_at" search:"deleted" operator:"not-null"`
+	Created  *time.Time `db:"created" search:"created_from" operator:"gte"`
+	Period   *struct {
+		UpdatedFrom *time.Time `db:"updated" search:"updated_from" operator:"gte"`
+	} `logic:"or"`
+	Untagged string
+}
+
+func Test_DecodeSearchValues(t *testing.T) {
+	values := url.Values{
+		"user_ids":     {"1", "2,3"},
+		"age_range":    {"20,30"},
+		"name":         {"tre,wanek"},
+		"score":        {"1.5"},
+		"deleted":      {"false"},
+		"created_from": {"2020-01-02"},
+		"updated_from": {"2020-01-02T03:04:05Z"},
+		"Untagged":     {"ignored"},
+	}
+
+	var param decoderTestParameter
+	if err := DecodeSearchValues(values, &param); err != nil {
+		t.Fatal(err)
+	}
+
+	name, deleted := "tre,wanek", false
+	created := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	updated := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	expected := decoderTestParameter{
+		UserIDs:  []int{1, 2, 3},
+		AgeRange: &[2]int{20, 30},
+		Name:     &name,
+		Score:    1.5,
+		Deleted:  &deleted,
+		Created:  &created,
+	}
+	expected.Period = &struct {
+		UpdatedFrom *time.Time `db:"updated" search:"updated_from" operator:"gte"`
+	}{&updated}
+
+	if !reflect.DeepEqual(expected, param) {
+		t.Logf("\nexpected: %+v\nactual  : %+v", expected, param)
+		t.Fail()
+	}
+}
+
+func Test_DecodeSearchValues_Empty(t *testing.T) {
+	var param decoderTestParameter
+	if err := DecodeSearchValues(url.Values{"name": {""}}, &param); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoderTestParameter{}, param) {
+		t.Logf("empty value should be skipped. actual: %+v", param)
+		t.Fail()
+	}
+}
+
+func Test_DecodeSearchValues_Errors(t *testing.T) {
+	values := url.Values{
+		"user_ids":     {"1,a"},
+		"age_range":    {"20"},
+		"deleted":      {"maybe"},
+		"created_from": {"yesterday"},
+		"name":         {"ok"},
+	}
+
+	var param decoderTestParameter
+	err := DecodeSearchValues(values, &param)
+
+	var errs DecodeErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected DecodeErrors, actual: %v", err)
+	}
+
+	fields := make([]string, 0, len(errs))
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	if expected := []string{"user_ids", "age_range", "deleted", "created_from"}; !reflect.DeepEqual(expected, fields) {
+		t.Logf("expected: %v, actual: %v", expected, fields)
+		t.Fail()
+	}
+
+	if err := DecodeSearchValues(values, param); err != DecodeTargetErr {
+		t.Logf("expected DecodeTargetErr, actual: %v", err)
+		t.Fail()
+	}
+}
+
+func Test_DecodeSearchValues_BetweenLength(t *testing.T) {
+	type SearchUsersParameter struct {
+		AgeRange []int `db:"age" search:"age_range" operator:"between"`
+		Name     *int  `db:"name" search:"name" operator:"eq"`
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"one value", "20", false},
+		{"two values", "20,30", true},
+		{"three values", "20,30,40", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param SearchUsersParameter
+			err := DecodeSearchValues(url.Values{"age_range": {tt.value}, "name": {"a"}}, &param)
+			if tt.valid {
+				if !reflect.DeepEqual([]int{20, 30}, param.AgeRange) {
+					t.Logf("unexpected result: %v, %v", param.AgeRange, err)
+					t.Fail()
+				}
+				return
+			}
+
+			var errs DecodeErrors
+			if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Field != "age_range" || !errors.Is(errs[0], BetweenValueErr) {
+				t.Logf("expected BetweenValueErr of age_range with error of name, actual: %v", err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func Test_DecodeSearchJSON(t *testing.T) {
+	var param decoderTestParameter
+	err := DecodeSearchJSON([]byte(`{"user_ids": [1, 2], "age_range": [20, 30], "name": "tre", "score": 2, "deleted": true, "created_from": null}`), &param)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, deleted := "tre", true
+	expected := decoderTestParameter{
+		UserIDs:  []int{1, 2},
+		AgeRange: &[2]int{20, 30},
+		Name:     &name,
+		Score:    2,
+		Deleted:  &deleted,
+	}
+	if !reflect.DeepEqual(expected, param) {
+		t.Logf("\nexpected: %+v\nactual  : %+v", expected, param)
+		t.Fail()
+	}
+
+	var errs DecodeErrors
+	if err := DecodeSearchJSON([]byte(`{"score": "high"}`), &param); !errors.As(err, &errs) || errs[0].Field != "score" {
+		t.Logf("expected DecodeErrors, actual: %v", err)
+		t.Fail()
+	}
+}
+
+func Test_DecodeSearchJSON_NotScalar(t *testing.T) {
+	type parameter struct {
+		Name  *string  `db:"name" search:"name" operator:"eq"`
+		Names []string `db:"name" search:"names" operator:"in"`
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"object", `{"name": {"first": "tre"}}`},
+		{"array of objects", `{"names": [{"first": "tre"}]}`},
+		{"nested array", `{"names": [["tre"]]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param parameter
+			var errs DecodeErrors
+			err := DecodeSearchJSON([]byte(tt.data), &param)
+			if !errors.As(err, &errs) || len(errs) != 1 || !errors.Is(errs[0], JSONValueErr) {
+				t.Logf("expected DecodeErrors of JSONValueErr, actual: %v", err)
+				t.Fail()
+			}
+		})
+	}
+
+	// object of key which is not search tag is ignored
+	var param parameter
+	if err := DecodeSearchJSON([]byte(`{"paging": {"page": 1}, "name": "tre"}`), &param); err != nil || param.Name == nil || *param.Name != "tre" {
+		t.Logf("expected name without error, actual: %+v, %v", param, err)
+		t.Fail()
+	}
+}
+
+func Test_DecodeSearchJSON_Comma(t *testing.T) {
+	type parameter struct {
+		Names []string `db:"name" search:"names" operator:"in"`
+	}
+
+	var param parameter
+	if err := DecodeSearchJSON([]byte(`{"names": ["Smith, John", "Doe"]}`), &param); err != nil {
+		t.Fatal(err)
+	}
+	// element of array is not split
+	expected := []string{"Smith, John", "Doe"}
+	if !reflect.DeepEqual(expected, param.Names) {
+		t.Logf("expected: %q, actual: %q", expected, param.Names)
+		t.Fail()
+	}
+
+	// query string is comma separated
+	param = parameter{}
+	if err := DecodeSearchValues(url.Values{"names": {"Smith, John"}}, &param); err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"Smith", "John"}
+	if !reflect.DeepEqual(expected, param.Names) {
+		t.Logf("expected: %q, actual: %q", expected, param.Names)
+		t.Fail()
+	}
+}
@@ -1,9 +1,13 @@
 package query_builder
 
-import "strings"
+import (
+	"context"
+	"strings"
+)
 
 type DeleteQueryBuilder struct {
 	*queryBuilder
+	hard bool
 }
 
 func NewDeleteQueryBuilder() *DeleteQueryBuilder {
@@ -16,6 +20,7 @@ func NewDeleteQueryBuilder() *DeleteQueryBuilder {
 func (builder *DeleteQueryBuilder) copy() *DeleteQueryBuilder {
 	return &DeleteQueryBuilder{
 		builder.queryBuilder.copy(),
+		builder.hard,
 	}
 }
 
@@ -25,6 +30,84 @@ func (builder *DeleteQueryBuilder) Placeholder(placeholderType int) *DeleteQuery
 	return copied
 }
 
+// Default dialect is MySQL. dialect decides quote characters.
+func (builder *DeleteQueryBuilder) Dialect(dialect int) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setDialect(dialect)
+	return copied
+}
+
+// Default is QuoteNever. QuoteWhenNeeded quotes reserved words and identifiers including special characters.
+func (builder *DeleteQueryBuilder) Quote(quoteMode int) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.quote(quoteMode)
+	return copied
+}
+
+// Strict mode panics on Build when identifiers are not safe. expressions need to be wrapped by Raw.
+func (builder *DeleteQueryBuilder) Strict() *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setStrict()
+	return copied
+}
+
+// WhereInValues renders = ANY(bind) and WhereNotInValues renders <> ALL(bind) with slice value. PostgreSQL only
+func (builder *DeleteQueryBuilder) ArrayBinding() *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setArrayBinding()
+	return copied
+}
+
+// soft delete column of this builder. overrides SetSoftDelete.
+// ex. SoftDelete("deleted_at") => UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE user_id = ? AND deleted_at IS NULL;
+func (builder *DeleteQueryBuilder) SoftDelete(column string) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.softDelete(column)
+	return copied
+}
+
+// overrides columns of SetDefaultAuditColumns for soft delete. &AuditColumns{} disables.
+func (builder *DeleteQueryBuilder) Audit(columns *AuditColumns) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setAudit(columns)
+	return copied
+}
+
+// columns of SetDefaultAuditColumns are set by soft delete. ex. SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+func (builder *DeleteQueryBuilder) DefaultAudit() *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setDefaultAudit()
+	return copied
+}
+
+// actor of ContextWithActor is bound to updated_by of soft delete
+func (builder *DeleteQueryBuilder) Context(ctx context.Context) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setContext(ctx)
+	return copied
+}
+
+// DELETE even if soft delete is configured
+func (builder *DeleteQueryBuilder) HardDelete() *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.hard = true
+	return copied
+}
+
+// scopes are added to WHERE with table on Build. ex. Scope(ActiveScope)
+func (builder *DeleteQueryBuilder) Scope(scopes ...Scope) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.scope(scopes...)
+	return copied
+}
+
+// removes mandatory scopes of SetMandatoryScope by name, all scopes without names. ex. Unscoped("tenant")
+func (builder *DeleteQueryBuilder) Unscoped(names ...string) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.unscope(names...)
+	return copied
+}
+
 func (builder *DeleteQueryBuilder) Table(tableName string) *DeleteQueryBuilder {
 	copied := builder.copy()
 	copied.queryBuilder = builder.table(tableName)
@@ -37,6 +120,38 @@ func (builder *DeleteQueryBuilder) Where(column, operator string, bind ...string
 	return copied
 }
 
+// ex. WhereRaw(Raw("LOWER(email)"), Equal, "email") => WHERE LOWER(email) = ?
+func (builder *DeleteQueryBuilder) WhereRaw(expression RawExpr, operator string, bind ...string) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereRaw("AND", expression, operator, bind...)
+	return copied
+}
+
+// ex. WhereCondition(AnyOf(Cond("name", Like), Cond("email", Like))) => WHERE (name LIKE ? OR email LIKE ?)
+func (builder *DeleteQueryBuilder) WhereCondition(condition *Condition) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereCondition("AND", condition)
+	return copied
+}
+
+func (builder *DeleteQueryBuilder) OrCondition(condition *Condition) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereCondition("OR", condition)
+	return copied
+}
+
+func (builder *DeleteQueryBuilder) WhereInValues(column string, values interface{}, bind ...string) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereInValues(In, column, values, bind...)
+	return copied
+}
+
+func (builder *DeleteQueryBuilder) WhereNotInValues(column string, values interface{}, bind ...string) *DeleteQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereInValues(NotIn, column, values, bind...)
+	return copied
+}
+
 func (builder *DeleteQueryBuilder) WhereIn(column string, listLength int, bind ...string) *DeleteQueryBuilder {
 	copied := builder.copy()
 	copied.queryBuilder = builder.whereIn(column, listLength, bind...)
@@ -49,13 +164,53 @@ func (builder *DeleteQueryBuilder) WhereNotIn(column string, listLength int, bin
 	return copied
 }
 
+// returns error when identifiers are not safe. Strict mode uses this on Build.
+func (builder *DeleteQueryBuilder) Validate() error {
+	return builder.validate()
+}
+
+// args in placeholder order for Question and DollarNumber. values of WhereInValues are merged with namedArgs.
+// query is built as Named and compiled, so binds need to be unique.
+// soft delete time is bound only when Now of AuditColumns is set, same as updated_at of audit.
+func (builder *DeleteQueryBuilder) Args(namedArgs ...map[string]interface{}) ([]interface{}, error) {
+	builder = &DeleteQueryBuilder{builder.freezeClock(), builder.hard}
+	values := builder.queryBuilder
+	if !builder.hard {
+		values = builder.softDeleted()
+	}
+	_, args, err := Compile(builder.Placeholder(Named).Build(), values.getNamedArgs(namedArgs), Question)
+	return args, err
+}
+
 func (builder *DeleteQueryBuilder) Build() string {
+	// placeholders are numbered on the copy, so Build can be called repeatedly
+	builder = builder.copy()
+
+	column := builder.getSoftDeleteColumn()
+	soft := column != "" && !builder.hard
+	if soft {
+		builder = &DeleteQueryBuilder{builder.softDeleted().softDeleteScope(false).scoped(), builder.hard}
+	} else {
+		builder = &DeleteQueryBuilder{builder.scoped(), builder.hard}
+	}
+
 	if builder.tableName == "" {
 		panic("target table is empty!!!")
 	}
 
+	if builder.strict {
+		if err := builder.Validate(); err != nil {
+			panic(err)
+		}
+	}
+
 	copied := builder.copy()
-	copied.query = append(copied.query, "DELETE", "FROM", builder.tableName)
+	if soft {
+		copied.query = append(copied.query, "UPDATE", builder.quoteIdentifier(builder.tableName))
+		copied.query = append(copied.query, builder.getSetParagraphs(builder.columns...))
+	} else {
+		copied.query = append(copied.query, "DELETE", "FROM", builder.quoteIdentifier(builder.tableName))
+	}
 
 	if len(builder.whereConditions) > 0 {
 		copied.query = append(copied.query, builder.getWhereParagraphs()...)
@@ -126,3 +126,18 @@ func Test_DeleteQueryBuilder_WhereNotIn(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func Test_DeleteQueryBuilder_Quote(t *testing.T) {
+	testCommonFunc(
+		t,
+		`DELETE FROM "user" WHERE "Name" = $1;`,
+		NewDeleteQueryBuilder().
+			Dialect(PostgreSQL).
+			Placeholder(DollarNumber).
+			Quote(QuoteWhenNeeded).
+			Table("user").
+			Where("Name", Equal).
+			Build(),
+		false,
+	)
+}
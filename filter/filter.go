@@ -0,0 +1,284 @@
+// Package filter compiles JSON filter tree into query_builder conditions.
+//
+//	{"and": [{"field": "age", "op": "gte", "value": 18}, {"or": [{"field": "name", "op": "prefix", "value": "tre"}, ...]}]}
+//
+// fields and operators are restricted by Allowlist. bind names are generated as filter1, filter2_1, ...
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	query_builder "github.com/trewanek/query-builder"
+)
+
+var (
+	InvalidNodeErr         = fmt.Errorf("filter node should have one of and, or, field")
+	UnknownFieldErr        = fmt.Errorf("filter field is not allowed")
+	UnsupportedOperatorErr = fmt.Errorf("filter operator is not allowed")
+	InvalidValueErr        = fmt.Errorf("filter value is invalid")
+)
+
+// op of filter => const.go operator
+var operators = map[string]string{
+	"eq":       query_builder.Equal,
+	"ne":       query_builder.NotEqual,
+	"gt":       query_builder.GraterThan,
+	"gte":      query_builder.GraterThanEqual,
+	"lt":       query_builder.LessThan,
+	"lte":      query_builder.LessThanEqual,
+	"like":     query_builder.Like,
+	"not-like": query_builder.NotLike,
+	"is-null":  query_builder.IsNull,
+	"not-null": query_builder.IsNotNull,
+	"in":       query_builder.In,
+	"not-in":   query_builder.NotIn,
+	"between":  query_builder.Between,
+	"prefix":   query_builder.Like,
+	"suffix":   query_builder.Like,
+	"contains": query_builder.Like,
+}
+
+type Error struct {
+	Path string
+	Err  error
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s: %v", err.Path, err.Err)
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+type Field struct {
+	Column string
+	// allowed ops. ex. []string{"eq", "in"}. empty allows all ops.
+	Operators []string
+}
+
+// api field name => Field
+type Allowlist map[string]Field
+
+type Result struct {
+	Condition *query_builder.Condition
+	// in placeholder order of Condition, for Question and DollarNumber
+	Args []interface{}
+	// for Named
+	NamedArgs map[string]interface{}
+}
+
+// input is json([]byte, string) or map[string]interface{}.
+// lists of map input can be any slice or array, values can be any bool, number, string or time.Time.
+func Compile(input interface{}, allowlist Allowlist) (*Result, error) {
+	node, err := decode(input)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := &compiler{allowlist: allowlist, result: &Result{NamedArgs: make(map[string]interface{})}}
+	condition, err := compiler.compile(node, "$")
+	if err != nil {
+		return nil, err
+	}
+	compiler.result.Condition = condition
+	return compiler.result, nil
+}
+
+// compiles input and appends the condition to builder by WhereCondition
+func Apply(builder *query_builder.SelectQueryBuilder, input interface{}, allowlist Allowlist) (*query_builder.SelectQueryBuilder, *Result, error) {
+	result, err := Compile(input, allowlist)
+	if err != nil {
+		return nil, nil, err
+	}
+	return builder.WhereCondition(result.Condition), result, nil
+}
+
+func decode(input interface{}) (interface{}, error) {
+	var data []byte
+	switch v := input.(type) {
+	case map[string]interface{}:
+		return v, nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, &Error{Path: "$", Err: InvalidNodeErr}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var node interface{}
+	if err := decoder.Decode(&node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+type compiler struct {
+	allowlist Allowlist
+	result    *Result
+	bindNum   int
+}
+
+func (compiler *compiler) compile(node interface{}, path string) (*query_builder.Condition, error) {
+	m, ok := node.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return nil, &Error{Path: path, Err: InvalidNodeErr}
+	}
+
+	if children, ok := m["and"]; ok {
+		return compiler.compileGroup(children, path+".and", query_builder.AllOf)
+	}
+	if children, ok := m["or"]; ok {
+		return compiler.compileGroup(children, path+".or", query_builder.AnyOf)
+	}
+	if _, ok := m["field"]; ok {
+		return compiler.compilePredicate(m, path)
+	}
+	return nil, &Error{Path: path, Err: InvalidNodeErr}
+}
+
+func (compiler *compiler) compileGroup(
+	children interface{},
+	path string,
+	combine func(...*query_builder.Condition) *query_builder.Condition,
+) (*query_builder.Condition, error) {
+	list, ok := toList(children)
+	if !ok || len(list) == 0 {
+		return nil, &Error{Path: path, Err: InvalidNodeErr}
+	}
+
+	conditions := make([]*query_builder.Condition, 0, len(list))
+	for index, child := range list {
+		condition, err := compiler.compile(child, fmt.Sprintf("%s[%d]", path, index))
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return combine(conditions...), nil
+}
+
+func (compiler *compiler) compilePredicate(m map[string]interface{}, path string) (*query_builder.Condition, error) {
+	name, _ := m["field"].(string)
+	field, ok := compiler.allowlist[name]
+	if !ok {
+		return nil, &Error{Path: path + ".field", Err: fmt.Errorf("%w: %q", UnknownFieldErr, name)}
+	}
+
+	op, _ := m["op"].(string)
+	operator, ok := operators[op]
+	if !ok || !field.allows(op) {
+		return nil, &Error{Path: path + ".op", Err: fmt.Errorf("%w: %q", UnsupportedOperatorErr, op)}
+	}
+
+	value := m["value"]
+	switch operator {
+	case query_builder.IsNull, query_builder.IsNotNull:
+		// false means opposite, same as search struct
+		if b, ok := value.(bool); ok && !b {
+			operator = map[string]string{query_builder.IsNull: query_builder.IsNotNull, query_builder.IsNotNull: query_builder.IsNull}[operator]
+		}
+		return query_builder.Cond(field.Column, operator), nil
+	case query_builder.In, query_builder.NotIn, query_builder.Between:
+		list, ok := toList(value)
+		if !ok || len(list) == 0 || (operator == query_builder.Between && len(list) != 2) {
+			return nil, &Error{Path: path + ".value", Err: InvalidValueErr}
+		}
+		bind := compiler.nextBind() + "_"
+		for index, element := range list {
+			if err := compiler.addArg(bind+strconv.Itoa(index+1), element, path+".value"); err != nil {
+				return nil, err
+			}
+		}
+		if operator == query_builder.In {
+			return query_builder.CondIn(field.Column, len(list), bind), nil
+		}
+		if operator == query_builder.NotIn {
+			return query_builder.CondNotIn(field.Column, len(list), bind), nil
+		}
+		return query_builder.Cond(field.Column, operator, bind), nil
+	}
+
+	bind := compiler.nextBind()
+	if op == "prefix" || op == "suffix" || op == "contains" {
+		s, ok := value.(string)
+		if !ok {
+			return nil, &Error{Path: path + ".value", Err: InvalidValueErr}
+		}
+		if err := compiler.addArg(bind, query_builder.LikePattern(op, s), path+".value"); err != nil {
+			return nil, err
+		}
+		return query_builder.CondLikePattern(field.Column, bind), nil
+	}
+	if err := compiler.addArg(bind, value, path+".value"); err != nil {
+		return nil, err
+	}
+	return query_builder.Cond(field.Column, operator, bind), nil
+}
+
+func (compiler *compiler) nextBind() string {
+	compiler.bindNum++
+	return "filter" + strconv.Itoa(compiler.bindNum)
+}
+
+func (compiler *compiler) addArg(bind string, value interface{}, path string) error {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			value = i
+		} else if f, err := v.Float64(); err == nil {
+			value = f
+		}
+	case string, bool, time.Time:
+	default:
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
+		default:
+			return &Error{Path: path, Err: InvalidValueErr}
+		}
+	}
+
+	compiler.result.Args = append(compiler.result.Args, value)
+	compiler.result.NamedArgs[bind] = value
+	return nil
+}
+
+// []interface{} of JSON, or any slice and array of map input. ex. []int, []map[string]interface{}
+func toList(value interface{}) ([]interface{}, bool) {
+	if list, ok := value.([]interface{}); ok {
+		return list, true
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	list := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		list = append(list, v.Index(i).Interface())
+	}
+	return list, true
+}
+
+func (field Field) allows(op string) bool {
+	if len(field.Operators) == 0 {
+		return true
+	}
+	for _, allowed := range field.Operators {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
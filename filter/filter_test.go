@@ -0,0 +1,149 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	query_builder "github.com/trewanek/query-builder"
+)
+
+var allowlist = Allowlist{
+	"age":     {Column: "users.age", Operators: []string{"eq", "gte", "lte", "between"}},
+	"name":    {Column: "users.name", Operators: []string{"eq", "prefix", "contains"}},
+	"status":  {Column: "users.status", Operators: []string{"in", "not-in"}},
+	"deleted": {Column: "users.deleted_at", Operators: []string{"is-null"}},
+}
+
+func Test_Apply(t *testing.T) {
+	input := `{"and": [
+		{"field": "age", "op": "gte", "value": 18},
+		{"or": [
+			{"field": "name", "op": "prefix", "value": "tre_"},
+			{"field": "status", "op": "in", "value": ["active", "pending"]}
+		]},
+		{"field": "age", "op": "between", "value": [20, 30.5]},
+		{"field": "deleted", "op": "is-null", "value": false}
+	]}`
+
+	builder, result, err := Apply(
+		query_builder.NewSelectQueryBuilder().Table("users").Where("users.tenant_id", query_builder.Equal),
+		input,
+		allowlist,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "SELECT users.* FROM users WHERE users.tenant_id = $1 " +
+		"AND (users.age >= $2 AND (users.name LIKE $3 ESCAPE '\\\\' OR users.status IN ($4, $5)) AND users.age BETWEEN $6 AND $7 AND users.deleted_at IS NOT NULL);"
+	if actual := builder.Placeholder(query_builder.DollarNumber).Build(); actual != expected {
+		t.Logf("\nexpected: %s\nactual  : %s", expected, actual)
+		t.Fail()
+	}
+
+	expectedNamed := "SELECT users.* FROM users WHERE users.tenant_id = :users.tenant_id " +
+		"AND (users.age >= :filter1 AND (users.name LIKE :filter2 ESCAPE '\\\\' OR users.status IN (:filter3_1, :filter3_2)) AND users.age BETWEEN :filter4_1 AND :filter4_2 AND users.deleted_at IS NOT NULL);"
+	if actual := builder.Placeholder(query_builder.Named).Build(); actual != expectedNamed {
+		t.Logf("\nexpected: %s\nactual  : %s", expectedNamed, actual)
+		t.Fail()
+	}
+
+	expectedArgs := []interface{}{int64(18), `tre\_%`, "active", "pending", int64(20), 30.5}
+	if !reflect.DeepEqual(expectedArgs, result.Args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, result.Args)
+		t.Fail()
+	}
+
+	expectedNamedArgs := map[string]interface{}{
+		"filter1":   int64(18),
+		"filter2":   `tre\_%`,
+		"filter3_1": "active",
+		"filter3_2": "pending",
+		"filter4_1": int64(20),
+		"filter4_2": 30.5,
+	}
+	if !reflect.DeepEqual(expectedNamedArgs, result.NamedArgs) {
+		t.Logf("expected: %v, actual: %v", expectedNamedArgs, result.NamedArgs)
+		t.Fail()
+	}
+}
+
+func Test_Compile_Map(t *testing.T) {
+	result, err := Compile(map[string]interface{}{"field": "name", "op": "eq", "value": "trewanek"}, allowlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "SELECT users.* FROM users WHERE users.name = ?;"
+	if actual := query_builder.NewSelectQueryBuilder().Table("users").WhereCondition(result.Condition).Build(); actual != expected {
+		t.Logf("\nexpected: %s\nactual  : %s", expected, actual)
+		t.Fail()
+	}
+	if !reflect.DeepEqual([]interface{}{"trewanek"}, result.Args) {
+		t.Logf("actual: %v", result.Args)
+		t.Fail()
+	}
+}
+
+func Test_Compile_MapGoTypes(t *testing.T) {
+	created := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	allowlist := Allowlist{
+		"age":     {Column: "users.age"},
+		"status":  {Column: "users.status"},
+		"created": {Column: "users.created_at"},
+	}
+	input := map[string]interface{}{"and": []map[string]interface{}{
+		{"field": "age", "op": "in", "value": []int{20, 30}},
+		{"field": "status", "op": "eq", "value": int32(1)},
+		{"field": "created", "op": "gte", "value": created},
+		{"field": "age", "op": "between", "value": [2]uint8{1, 2}},
+	}}
+
+	result, err := Compile(input, allowlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "SELECT users.* FROM users WHERE (users.age IN (?, ?) AND users.status = ? AND users.created_at >= ? AND users.age BETWEEN ? AND ?);"
+	if actual := query_builder.NewSelectQueryBuilder().Table("users").WhereCondition(result.Condition).Build(); actual != expected {
+		t.Logf("\nexpected: %s\nactual  : %s", expected, actual)
+		t.Fail()
+	}
+	expectedArgs := []interface{}{20, 30, int32(1), created, uint8(1), uint8(2)}
+	if !reflect.DeepEqual(expectedArgs, result.Args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, result.Args)
+		t.Fail()
+	}
+}
+
+func Test_Compile_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		path     string
+		expected error
+	}{
+		{"unknown field", `{"field": "password", "op": "eq", "value": "x"}`, "$.field", UnknownFieldErr},
+		{"not allowed op", `{"and": [{"field": "age", "op": "like", "value": "1"}]}`, "$.and[0].op", UnsupportedOperatorErr},
+		{"unknown op", `{"field": "age", "op": "; DROP", "value": 1}`, "$.op", UnsupportedOperatorErr},
+		{"empty group", `{"or": []}`, "$.or", InvalidNodeErr},
+		{"unknown node", `{"not": {"field": "age"}}`, "$", InvalidNodeErr},
+		{"empty in", `{"field": "status", "op": "in", "value": []}`, "$.value", InvalidValueErr},
+		{"between length", `{"field": "age", "op": "between", "value": [1]}`, "$.value", InvalidValueErr},
+		{"object value", `{"field": "age", "op": "eq", "value": {"a": 1}}`, "$.value", InvalidValueErr},
+		{"null value", `{"field": "age", "op": "eq", "value": null}`, "$.value", InvalidValueErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.input, allowlist)
+			var filterErr *Error
+			if !errors.As(err, &filterErr) || filterErr.Path != tt.path || !errors.Is(err, tt.expected) {
+				t.Logf("expected: %s %v, actual: %v", tt.path, tt.expected, err)
+				t.Fail()
+			}
+		})
+	}
+}
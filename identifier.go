@@ -0,0 +1,379 @@
+package query_builder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	plainIdentifierRegexp         = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+	caseSensitiveIdentifierRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_$]*$`)
+	strictIdentifierRegexp        = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_$]*\.){0,2}([A-Za-z_][A-Za-z0-9_$]*|\*)$`)
+	bindRegexp                    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	functionRegexp                = regexp.MustCompile(`^.*\(.*\)`)
+	literalRegexp                 = regexp.MustCompile(`^('([^']|'')*'|-?[0-9]+(\.[0-9]+)?|(?i:NULL|TRUE|FALSE))$`)
+	frameBoundRegexp              = regexp.MustCompile(`^(UNBOUNDED PRECEDING|UNBOUNDED FOLLOWING|CURRENT ROW|[0-9]+ (PRECEDING|FOLLOWING))$`)
+)
+
+var reservedWords = map[string]bool{
+	"add": true, "all": true, "alter": true, "and": true, "any": true, "as": true, "asc": true,
+	"between": true, "by": true, "case": true, "check": true, "column": true, "constraint": true,
+	"create": true, "cross": true, "current_date": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "database": true, "default": true, "delete": true, "desc": true,
+	"distinct": true, "drop": true, "else": true, "end": true, "exists": true, "false": true,
+	"fetch": true, "for": true, "foreign": true, "from": true, "full": true, "grant": true,
+	"group": true, "having": true, "in": true, "index": true, "inner": true, "insert": true,
+	"interval": true, "into": true, "is": true, "join": true, "key": true, "left": true,
+	"like": true, "limit": true, "lock": true, "natural": true, "not": true, "null": true,
+	"offset": true, "on": true, "or": true, "order": true, "outer": true, "over": true,
+	"partition": true, "primary": true, "range": true, "read": true, "references": true,
+	"right": true, "row": true, "rows": true, "schema": true, "select": true, "session_user": true,
+	"set": true, "table": true, "then": true, "to": true, "true": true, "union": true,
+	"unique": true, "update": true, "user": true, "using": true, "values": true, "when": true,
+	"where": true, "window": true, "with": true,
+}
+
+// operators of const.go. operator is rendered as it is, so strict mode accepts only these
+var operators = map[string]bool{
+	Equal: true, GraterThan: true, GraterThanEqual: true, LessThan: true, LessThanEqual: true,
+	NotEqual: true, Like: true, NotLike: true, IsNull: true, IsNotNull: true, In: true,
+	NotIn: true, Between: true,
+}
+
+var joinTypes = map[string]bool{LeftJoin: true, RightJoin: true, InnerJoin: true}
+
+// Quote mode. Default is QuoteNever
+const (
+	QuoteNever = iota
+	QuoteWhenNeeded
+	QuoteAlways
+)
+
+// RawExpr is expression which is rendered as it is, without table prefix and quote.
+// it is made only by Raw, so string of request input never becomes expression.
+type RawExpr struct {
+	expression string
+}
+
+// Strict mode accepts only identifiers and Raw expressions. raw is remembered by its position, not by string.
+// ex. ColumnRaw(Raw("COUNT(*) AS cnt")), WhereRaw(Raw("LOWER(name)"), Equal, "name")
+func Raw(expression string) RawExpr {
+	return RawExpr{expression: expression}
+}
+
+func (raw RawExpr) String() string {
+	return raw.expression
+}
+
+// raw column index set is copied on write
+func copyRawColumns(rawColumns map[int]bool) map[int]bool {
+	copied := make(map[int]bool, len(rawColumns)+1)
+	for index := range rawColumns {
+		copied[index] = true
+	}
+	return copied
+}
+
+// ex. schema.table.column => `schema`.`table`.`column`, first name => `first name`
+// * is rendered as it is. Raw expression is rendered by caller without this.
+func (builder *queryBuilder) quoteIdentifier(identifier string) string {
+	if builder.quoteMode == QuoteNever {
+		return identifier
+	}
+
+	parts := builder.splitIdentifier(identifier)
+	for index, part := range parts {
+		parts[index] = builder.quoteIdentifierPart(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// dot in quoted part is not separator. ex. "my.schema".users => ["my.schema", users]
+func (builder *queryBuilder) splitIdentifier(identifier string) []string {
+	open, close := builder.getQuoteCharacters()
+	parts := make([]string, 0, 3)
+	start := 0
+	quoted := false
+	for index := 0; index < len(identifier); index++ {
+		c := identifier[index : index+1]
+		switch {
+		case !quoted && index == start && c == open:
+			quoted = true
+		case quoted && c == close && strings.HasPrefix(identifier[index+1:], close):
+			// doubled close is escaped close
+			index++
+		case quoted && c == close:
+			quoted = false
+		case !quoted && c == ".":
+			parts = append(parts, identifier[start:index])
+			start = index + 1
+		}
+	}
+	return append(parts, identifier[start:])
+}
+
+// ex. "created, user_id" => `created`, `user_id`. function is rendered as it is same as select columns
+func (builder *queryBuilder) quoteIdentifiers(identifiers string) string {
+	if builder.quoteMode == QuoteNever {
+		return identifiers
+	}
+
+	split := splitOutsideParentheses(identifiers)
+	for index, identifier := range split {
+		identifier = strings.TrimSpace(identifier)
+		if !functionRegexp.MatchString(identifier) {
+			identifier = builder.quoteIdentifier(identifier)
+		}
+		split[index] = identifier
+	}
+	return strings.Join(split, ", ")
+}
+
+// comma in function is not separator. ex. "COALESCE(a, b), c" => ["COALESCE(a, b)", " c"]
+func splitOutsideParentheses(s string) []string {
+	parts := make([]string, 0, 1)
+	start := 0
+	depth := 0
+	for index := 0; index < len(s); index++ {
+		switch s[index] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:index])
+				start = index + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func (builder *queryBuilder) validate() error {
+	if err := builder.validateIdentifier(builder.tableName, false); err != nil {
+		return err
+	}
+
+	for index, column := range builder.columns {
+		// column of window is alias
+		if window := builder.windowColumns[index]; window != nil {
+			if err := builder.validateWindow(window); err != nil {
+				return err
+			}
+			if column == "" {
+				continue
+			}
+		}
+		if err := builder.validateIdentifier(column, builder.rawColumns[index]); err != nil {
+			return err
+		}
+		if alias, ok := builder.columnAliases[index]; ok {
+			if err := builder.validateIdentifier(alias, false); err != nil {
+				return err
+			}
+		}
+		if expression := builder.cases[index]; expression != nil {
+			if err := builder.validateCase(expression); err != nil {
+				return err
+			}
+		}
+	}
+	for _, sub := range builder.subQueries {
+		if err := sub.Validate(); err != nil {
+			return err
+		}
+	}
+	return builder.validateConditions(builder.whereConditions)
+}
+
+func (builder *queryBuilder) validateConditions(conditions []map[string]string) error {
+	for _, condition := range conditions {
+		if err := builder.validateIdentifier(condition["column"], condition["raw"] != ""); err != nil {
+			return err
+		}
+		op := condition["operator"]
+		if !operators[op] {
+			return fmt.Errorf("%w: operator %q", InvalidIdentifierErr, op)
+		}
+		if condition["subQuery"] != "" || op == IsNull || op == IsNotNull {
+			continue
+		}
+		if err := builder.validateBind(condition["bind"]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (builder *queryBuilder) validateCase(expression *CaseExpression) error {
+	for _, when := range expression.whens {
+		if err := builder.validateConditions(when["conditions"].([]map[string]string)); err != nil {
+			return err
+		}
+		if err := builder.validateValue(when["result"].(string), when["raw"].(bool)); err != nil {
+			return err
+		}
+	}
+	if expression.elseResult == "" {
+		return nil
+	}
+	return builder.validateValue(expression.elseResult, expression.elseRaw)
+}
+
+// value of CASE result and window function. identifier or literal. ex. users.name, 'active', 1, NULL
+func (builder *queryBuilder) validateValue(value string, raw bool) error {
+	if raw {
+		return validateExpression(value)
+	}
+	if literalRegexp.MatchString(value) {
+		return nil
+	}
+	return builder.validateIdentifier(value, false)
+}
+
+// bind is rendered as it is by Named. ex. :{bind}
+func (builder *queryBuilder) validateBind(bind string) error {
+	if builder.placeholderType != Named || bindRegexp.MatchString(bind) {
+		return nil
+	}
+	return fmt.Errorf("%w: bind %q", InvalidIdentifierErr, bind)
+}
+
+// function and frame bounds are identifiers, literals or frame constants without Raw, name and columns are identifiers
+func (builder *queryBuilder) validateWindow(window *Window) error {
+	if window.function != "" {
+		if err := builder.validateValue(window.function, window.functionRaw); err != nil {
+			return err
+		}
+	}
+	for _, bound := range window.frameBounds {
+		if window.frameRaw {
+			if err := validateExpression(bound); err != nil {
+				return err
+			}
+		} else if !frameBoundRegexp.MatchString(bound) {
+			return fmt.Errorf("%w: frame %q", InvalidIdentifierErr, bound)
+		}
+	}
+	if window.name != "" {
+		if err := builder.validateIdentifier(window.name, false); err != nil {
+			return err
+		}
+	}
+	for _, column := range window.partitionBy {
+		if err := builder.validateIdentifier(column, false); err != nil {
+			return err
+		}
+	}
+	for _, order := range window.orderBy {
+		if order["order"] != Asc && order["order"] != Desc {
+			return fmt.Errorf("%w: order %q", InvalidIdentifierErr, order["order"])
+		}
+		if err := builder.validateIdentifier(order["column"], false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ex. "created, user_id"
+func (builder *queryBuilder) validateIdentifiers(identifiers string, raw bool) error {
+	if raw {
+		return validateExpression(identifiers)
+	}
+	for _, identifier := range strings.Split(identifiers, ",") {
+		if err := builder.validateIdentifier(strings.TrimSpace(identifier), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// raw is expression of Raw
+func (builder *queryBuilder) validateIdentifier(identifier string, raw bool) error {
+	if raw {
+		return validateExpression(identifier)
+	}
+	if !strictIdentifierRegexp.MatchString(identifier) {
+		return fmt.Errorf("%w: %q", InvalidIdentifierErr, identifier)
+	}
+	return nil
+}
+
+// statement terminator, comments and unbalanced quotes are suspicious
+func validateExpression(expression string) error {
+	var quote rune
+	runes := []rune(expression)
+	for index, r := range runes {
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		next := rune(0)
+		if index+1 < len(runes) {
+			next = runes[index+1]
+		}
+
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == ';' || r == '#':
+			return fmt.Errorf("%w: %q", SuspiciousExpressionErr, expression)
+		case r == '-' && next == '-', r == '/' && next == '*', r == '*' && next == '/':
+			return fmt.Errorf("%w: %q", SuspiciousExpressionErr, expression)
+		}
+	}
+
+	if quote != 0 {
+		return fmt.Errorf("%w: %q", SuspiciousExpressionErr, expression)
+	}
+	return nil
+}
+
+func (builder *queryBuilder) quoteIdentifierPart(part string) string {
+	open, close := builder.getQuoteCharacters()
+	if part == "*" || part == "" || isQuotedIdentifier(part, open, close) {
+		return part
+	}
+
+	if builder.quoteMode == QuoteWhenNeeded && !builder.needsQuote(part) {
+		return part
+	}
+	return open + strings.Replace(part, close, close+close, -1) + close
+}
+
+// close character inside needs to be doubled. ex. [we]]ird]
+func isQuotedIdentifier(part, open, close string) bool {
+	if len(part) < 2 || !strings.HasPrefix(part, open) || !strings.HasSuffix(part, close) {
+		return false
+	}
+	inner := part[len(open) : len(part)-len(close)]
+	return !strings.Contains(strings.Replace(inner, close+close, "", -1), close)
+}
+
+func (builder *queryBuilder) needsQuote(part string) bool {
+	if reservedWords[strings.ToLower(part)] {
+		return true
+	}
+	// PostgreSQL folds unquoted identifier to lower case
+	if builder.dialect == PostgreSQL {
+		return !caseSensitiveIdentifierRegexp.MatchString(part)
+	}
+	return !plainIdentifierRegexp.MatchString(part)
+}
+
+func (builder *queryBuilder) getQuoteCharacters() (string, string) {
+	switch builder.dialect {
+	case PostgreSQL:
+		return `"`, `"`
+	case SQLServer:
+		return "[", "]"
+	default:
+		return "`", "`"
+	}
+}
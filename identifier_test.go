@@ -0,0 +1,78 @@
+package query_builder
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_queryBuilder_quoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		dialect    int
+		quoteMode  int
+		identifier string
+		expected   string
+	}{
+		{"never", MySQL, QuoteNever, "order", "order"},
+		{"always mysql", MySQL, QuoteAlways, "users.name", "`users`.`name`"},
+		{"always postgres", PostgreSQL, QuoteAlways, "public.users.name", `"public"."users"."name"`},
+		{"always sql server", SQLServer, QuoteAlways, "dbo.users", "[dbo].[users]"},
+		{"asterisk", MySQL, QuoteAlways, "users.*", "`users`.*"},
+		{"when needed plain", MySQL, QuoteWhenNeeded, "users.name", "users.name"},
+		{"when needed reserved", MySQL, QuoteWhenNeeded, "users.order", "users.`order`"},
+		{"when needed reserved upper case", PostgreSQL, QuoteWhenNeeded, "USER", `"USER"`},
+		{"when needed mixed case mysql", MySQL, QuoteWhenNeeded, "UserName", "UserName"},
+		{"when needed mixed case postgres", PostgreSQL, QuoteWhenNeeded, "UserName", `"UserName"`},
+		{"when needed special character", MySQL, QuoteWhenNeeded, "user-name", "`user-name`"},
+		{"escape mysql", MySQL, QuoteAlways, "we`ird", "`we``ird`"},
+		{"escape postgres", PostgreSQL, QuoteAlways, `we"ird`, `"we""ird"`},
+		{"escape sql server", SQLServer, QuoteAlways, "we]ird", "[we]]ird]"},
+		{"already quoted", PostgreSQL, QuoteAlways, `"Users".name`, `"Users"."name"`},
+		{"already quoted with dot", PostgreSQL, QuoteAlways, `"my.schema".users`, `"my.schema"."users"`},
+		{"broken quote", MySQL, QuoteAlways, "`a`||(SELECT 1)||`b`", "```a``||(SELECT 1)||``b```"},
+		{"space", MySQL, QuoteAlways, "users.first name", "`users`.`first name`"},
+		{"space when needed", PostgreSQL, QuoteWhenNeeded, "first name", `"first name"`},
+		{"expression without raw", MySQL, QuoteAlways, "COUNT(*)", "`COUNT(*)`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := newQueryBuilder().setDialect(tt.dialect).quote(tt.quoteMode)
+			if err := checkQuery(tt.expected, builder.quoteIdentifier(tt.identifier)); err != nil {
+				t.Log(err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func Test_validateExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		valid      bool
+	}{
+		{"COUNT(*) AS cnt", true},
+		{"COALESCE(name, '') AS name", true},
+		{"CONCAT(name, '; -- #')", true},
+		{"name; DROP TABLE users", false},
+		{"name -- comment", false},
+		{"name /* comment */", false},
+		{"name # comment", false},
+		{"COALESCE(name, ')", false},
+		{`"name`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			err := validateExpression(tt.expression)
+			if tt.valid && err != nil {
+				t.Log(err)
+				t.Fail()
+			}
+			if !tt.valid && !errors.Is(err, SuspiciousExpressionErr) {
+				t.Logf("expected SuspiciousExpressionErr, actual: %v", err)
+				t.Fail()
+			}
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package query_builder
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
 
 type InsertQueryBuilder struct {
 	*queryBuilder
+	selectQueryBuilder *SelectQueryBuilder
 }
 
 func NewInsertQueryBuilder() *InsertQueryBuilder {
@@ -19,6 +21,7 @@ func NewInsertQueryBuilder() *InsertQueryBuilder {
 func (builder *InsertQueryBuilder) copy() *InsertQueryBuilder {
 	return &InsertQueryBuilder{
 		builder.queryBuilder.copy(),
+		builder.selectQueryBuilder,
 	}
 }
 
@@ -28,6 +31,48 @@ func (builder *InsertQueryBuilder) Placeholder(placeholderType int) *InsertQuery
 	return copied
 }
 
+// Default dialect is MySQL. dialect decides quote characters.
+func (builder *InsertQueryBuilder) Dialect(dialect int) *InsertQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setDialect(dialect)
+	return copied
+}
+
+// Default is QuoteNever. QuoteWhenNeeded quotes reserved words and identifiers including special characters.
+func (builder *InsertQueryBuilder) Quote(quoteMode int) *InsertQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.quote(quoteMode)
+	return copied
+}
+
+// Strict mode panics on Build when identifiers are not safe. expressions need to be wrapped by Raw.
+func (builder *InsertQueryBuilder) Strict() *InsertQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setStrict()
+	return copied
+}
+
+// overrides columns of SetDefaultAuditColumns. &AuditColumns{} disables.
+func (builder *InsertQueryBuilder) Audit(columns *AuditColumns) *InsertQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setAudit(columns)
+	return copied
+}
+
+// columns of SetDefaultAuditColumns are filled. builders without DefaultAudit or Audit have no audit columns
+func (builder *InsertQueryBuilder) DefaultAudit() *InsertQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setDefaultAudit()
+	return copied
+}
+
+// actor of ContextWithActor is bound to audit columns
+func (builder *InsertQueryBuilder) Context(ctx context.Context) *InsertQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setContext(ctx)
+	return copied
+}
+
 func (builder *InsertQueryBuilder) Table(tableName string) *InsertQueryBuilder {
 	copied := builder.copy()
 	copied.queryBuilder = builder.table(tableName)
@@ -36,7 +81,7 @@ func (builder *InsertQueryBuilder) Table(tableName string) *InsertQueryBuilder {
 
 func (builder *InsertQueryBuilder) Model(src interface{}, notIgnoreZeroValue ...bool) *InsertQueryBuilder {
 	copied := builder.copy()
-	copied.queryBuilder = builder.model(src, notIgnoreZeroValue...)
+	copied.queryBuilder = builder.model(src, modelInsert, nil, notIgnoreZeroValue...)
 	return copied
 }
 
@@ -52,11 +97,59 @@ func (builder *InsertQueryBuilder) Omit(columns ...string) *InsertQueryBuilder {
 	return copied
 }
 
+// INSERT INTO {table}({columns}) SELECT ...
+// select query is rendered by insert's placeholder, so that placeholders are numbered through whole query.
+func (builder *InsertQueryBuilder) FromSelect(selectQueryBuilder *SelectQueryBuilder) *InsertQueryBuilder {
+	if selectQueryBuilder == nil {
+		panic(SubQueryEmptyErr)
+	}
+	copied := builder.copy()
+	copied.selectQueryBuilder = selectQueryBuilder
+	return copied
+}
+
+// returns error when identifiers are not safe. Strict mode uses this on Build.
+func (builder *InsertQueryBuilder) Validate() error {
+	if err := builder.validate(); err != nil {
+		return err
+	}
+	if builder.selectQueryBuilder != nil {
+		return builder.selectQueryBuilder.Placeholder(builder.placeholderType).Validate()
+	}
+	return nil
+}
+
+// args in placeholder order for Question and DollarNumber. audit values and values of FromSelect are merged with namedArgs.
+func (builder *InsertQueryBuilder) Args(namedArgs ...map[string]interface{}) ([]interface{}, error) {
+	builder = &InsertQueryBuilder{builder.freezeClock(), builder.selectQueryBuilder}
+	values := builder.audited(modelInsert).getNamedArgs(namedArgs)
+	if builder.selectQueryBuilder != nil {
+		// values of select query are merged like subqueries
+		values = builder.selectQueryBuilder.getNamedArgs([]map[string]interface{}{values})
+	}
+	_, args, err := Compile(builder.Placeholder(Named).Build(), values, Question)
+	return args, err
+}
+
 func (builder *InsertQueryBuilder) Build() string {
+	// placeholders are numbered on the copy, so Build can be called repeatedly
+	builder = builder.copy()
+
+	// INSERT ... SELECT copies rows as it is
+	if builder.selectQueryBuilder == nil {
+		builder = &InsertQueryBuilder{builder.audited(modelInsert), nil}
+	}
+
 	if builder.tableName == "" {
 		panic("target table is empty!!!")
 	}
 
+	if builder.strict {
+		if err := builder.Validate(); err != nil {
+			panic(err)
+		}
+	}
+
 	if len(builder.columns) == 0 {
 		panic("target columns is empty!!!")
 	}
@@ -66,6 +159,12 @@ func (builder *InsertQueryBuilder) Build() string {
 
 	copied.query = append(copied.query, builder.getInsertIntoParagraphs()...)
 	copied.query = append(copied.query, builder.getTableAndColumnsParagraphs(builder.tableName, columns...))
+
+	if builder.selectQueryBuilder != nil {
+		copied.query = append(copied.query, builder.getSelectParagraph(columns...))
+		return strings.TrimRight(strings.Join(copied.query, " "), "") + ";"
+	}
+
 	copied.query = append(copied.query, builder.getValuesParagraphs(columns...))
 
 	if len(builder.whereConditions) > 0 {
@@ -83,17 +182,31 @@ func (builder *InsertQueryBuilder) getInsertIntoParagraphs() []string {
 }
 
 func (builder *InsertQueryBuilder) getTableAndColumnsParagraphs(tableName string, columns ...string) string {
-	return fmt.Sprintf("%s(%s)", tableName, strings.Join(columns, ", "))
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, builder.quoteIdentifier(column))
+	}
+	return fmt.Sprintf("%s(%s)", builder.quoteIdentifier(tableName), strings.Join(quoted, ", "))
 }
 
 func (builder *InsertQueryBuilder) getValuesParagraphs(columns ...string) string {
 	valuesContent := make([]string, 0, len(columns))
-	bind := "?"
 	for _, column := range columns {
-		if builder.placeholderType == Named {
-			bind = ":" + column
+		if expression, ok := builder.expressions[column]; ok {
+			valuesContent = append(valuesContent, expression)
+			continue
 		}
-		valuesContent = append(valuesContent, bind)
+		valuesContent = append(valuesContent, builder.getBind(column))
 	}
 	return fmt.Sprintf("VALUES(%s)", strings.Join(valuesContent, ", "))
 }
+
+func (builder *InsertQueryBuilder) getSelectParagraph(columns ...string) string {
+	if len(builder.selectQueryBuilder.columns) != len(columns) {
+		panic(ColumnCountMismatchErr)
+	}
+
+	var paragraph string
+	paragraph, builder.argNum = builder.selectQueryBuilder.Placeholder(builder.placeholderType).build(builder.argNum)
+	return paragraph
+}
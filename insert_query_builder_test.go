@@ -1,6 +1,11 @@
 package query_builder
 
-import "testing"
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
 
 func Test_InsertQueryBuilder_Column(t *testing.T) {
 	testCommonFunc(
@@ -25,6 +30,22 @@ func Test_InsertQueryBuilder_Column(t *testing.T) {
 	)
 }
 
+func Test_InsertQueryBuilder_DollarNumber(t *testing.T) {
+	builder := NewInsertQueryBuilder().
+		Placeholder(DollarNumber).
+		Table("users").
+		Column("name", "age", "sex")
+
+	for i := 0; i < 2; i++ {
+		testCommonFunc(
+			t,
+			"INSERT INTO users(name, age, sex) VALUES($1, $2, $3);",
+			builder.Build(),
+			false,
+		)
+	}
+}
+
 func Test_InsertQueryBuilder_Omit(t *testing.T) {
 	testCommonFunc(
 		t,
@@ -94,3 +115,175 @@ func Test_InsertQueryBuilder_Model(t *testing.T) {
 		true,
 	)
 }
+
+func Test_InsertQueryBuilder_ModelPointerAndNull(t *testing.T) {
+	type Profile struct {
+		UserID   string         `db:"user_id" table:"profiles"`
+		Nickname *string        `db:"nickname" table:"profiles"`
+		Age      *int           `db:"age" table:"profiles"`
+		Bio      sql.NullString `db:"bio" table:"profiles"`
+		Score    sql.NullInt64  `db:"score" table:"profiles"`
+		Note     *string        `db:"note" table:"profiles"`
+	}
+
+	nickname, age := "tre", 0
+	profile := Profile{
+		UserID:   "u1",
+		Nickname: &nickname,
+		Age:      &age,
+		Bio:      sql.NullString{String: "", Valid: true},
+		Score:    sql.NullInt64{Int64: 10, Valid: false},
+	}
+
+	testCommonFunc(
+		t,
+		"INSERT INTO profiles(user_id, nickname, age, bio) VALUES(?, ?, ?, ?);",
+		NewInsertQueryBuilder().
+			Table("profiles").
+			Model(profile).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE profiles SET user_id = ?, nickname = ?, age = ?, bio = ?, score = ?, note = ?;",
+		NewUpdateQueryBuilder().
+			Table("profiles").
+			Model(&profile, true).
+			Build(),
+		true,
+	)
+}
+
+func Test_InsertQueryBuilder_FromSelect(t *testing.T) {
+	testCommonFunc(
+		t,
+		"INSERT INTO archived_users(user_id, name) SELECT users.user_id, users.name FROM users WHERE age >= ?;",
+		NewInsertQueryBuilder().
+			Table("archived_users").
+			Column("user_id", "name").
+			FromSelect(
+				NewSelectQueryBuilder().
+					Table("users").
+					Column("user_id", "name").
+					Where("age", GraterThanEqual),
+			).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"INSERT INTO archived_users(user_id, name) SELECT users.user_id, users.name FROM users WHERE age >= $1 AND sex = $2 LIMIT $3;",
+		NewInsertQueryBuilder().
+			Placeholder(DollarNumber).
+			Table("archived_users").
+			Column("user_id", "name").
+			FromSelect(
+				NewSelectQueryBuilder().
+					Table("users").
+					Column("user_id", "name").
+					Where("age", GraterThanEqual).
+					Where("sex", Equal).
+					Limit(),
+			).
+			Build(),
+		false,
+	)
+}
+
+func Test_InsertQueryBuilder_FromSelectArgs(t *testing.T) {
+	builder := NewInsertQueryBuilder().
+		Placeholder(DollarNumber).
+		Table("archived_users").
+		Column("user_id", "name").
+		FromSelect(
+			NewSelectQueryBuilder().
+				Table("users").
+				Column("user_id", "name").
+				WhereInValues("user_id", []int{1, 2}).
+				Where("age", GraterThanEqual),
+		)
+
+	testCommonFunc(
+		t,
+		"INSERT INTO archived_users(user_id, name) SELECT users.user_id, users.name FROM users WHERE user_id IN ($1, $2) AND age >= $3;",
+		builder.Build(),
+		false,
+	)
+
+	// values of select query are merged with namedArgs
+	args, err := builder.Args(map[string]interface{}{"age": 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{1, 2, 20}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+}
+
+func Test_InsertQueryBuilder_FromSelectColumnCountMismatch(t *testing.T) {
+	defer func() {
+		err := recover()
+		if err != ColumnCountMismatchErr {
+			t.Log(err)
+			t.Fail()
+		}
+	}()
+
+	_ = NewInsertQueryBuilder().
+		Table("archived_users").
+		Column("user_id", "name").
+		FromSelect(NewSelectQueryBuilder().Table("users").Column("user_id")).
+		Build()
+}
+
+func Test_InsertQueryBuilder_FromSelectStrict(t *testing.T) {
+	builder := NewInsertQueryBuilder().
+		Table("archived_users").
+		Column("user_id").
+		FromSelect(NewSelectQueryBuilder().Table("users").Column("x; drop"))
+
+	if err := builder.Validate(); !errors.Is(err, InvalidIdentifierErr) {
+		t.Logf("expected: %v, actual: %v", InvalidIdentifierErr, err)
+		t.Fail()
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, InvalidIdentifierErr) {
+			t.Logf("expected: %v, actual: %v", InvalidIdentifierErr, err)
+			t.Fail()
+		}
+	}()
+	_ = builder.Strict().Build()
+}
+
+func Test_InsertQueryBuilder_Quote(t *testing.T) {
+	testCommonFunc(
+		t,
+		"INSERT INTO `users`(`name`, `order`) VALUES(?, ?);",
+		NewInsertQueryBuilder().
+			Quote(QuoteAlways).
+			Table("users").
+			Column("name", "order").
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		`INSERT INTO "Users"(name, "user") VALUES($1, $2);`,
+		NewInsertQueryBuilder().
+			Dialect(PostgreSQL).
+			Placeholder(DollarNumber).
+			Quote(QuoteWhenNeeded).
+			Table("Users").
+			Column("name", "user").
+			Build(),
+		false,
+	)
+}
@@ -0,0 +1,230 @@
+package query_builder
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// struct metadata is cached per type and shared by all builders
+var (
+	// reflect.Type => []modelField
+	modelFieldsCache sync.Map
+	// searchFieldsKey => []searchField
+	searchFieldsCache sync.Map
+	// reflect.Type => modelTable
+	modelTableCache sync.Map
+	tableNamerType  = reflect.TypeOf((*tableNamer)(nil)).Elem()
+)
+
+// usage of Model
+const (
+	modelSelect = iota
+	modelInsert
+	modelUpdate
+)
+
+// column of Model. index is field index path from model struct.
+type modelField struct {
+	index   []int
+	column  string
+	table   string
+	options map[string]bool
+}
+
+// fields of anonymous embedded struct and prefix tagged struct are collected recursively.
+// table tag is inherited from embedding field when field has no table tag.
+// shallower field shadows deeper field of same table and column like Go's promoted field, same depth fields are ignored.
+// ex. BaseModel `table:"users"`, Address Address `prefix:"address_"`
+func getModelFields(t reflect.Type) []modelField {
+	if cached, ok := modelFieldsCache.Load(t); ok {
+		return cached.([]modelField)
+	}
+
+	visible := visibleModelFields(collectModelFields(t, nil, "", ""))
+	modelFieldsCache.Store(t, visible)
+	return visible
+}
+
+// fields which are not shadowed by shallower field and not ambiguous at same depth.
+// same column of different tables is not shadowed. ex. users.id and orders.id of join model
+func visibleModelFields(fields []modelField) []modelField {
+	type key struct{ table, column string }
+	depths := make(map[key]int)
+	counts := make(map[key]int)
+	for _, field := range fields {
+		k := key{field.table, field.column}
+		depth, ok := depths[k]
+		switch {
+		case !ok || len(field.index) < depth:
+			depths[k] = len(field.index)
+			counts[k] = 1
+		case len(field.index) == depth:
+			counts[k]++
+		}
+	}
+
+	visible := make([]modelField, 0, len(fields))
+	for _, field := range fields {
+		k := key{field.table, field.column}
+		if len(field.index) == depths[k] && counts[k] == 1 {
+			visible = append(visible, field)
+		}
+	}
+	return visible
+}
+
+func collectModelFields(t reflect.Type, index []int, prefix, table string) []modelField {
+	fields := make([]modelField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		dbTag, options := parseDBTag(field.Tag.Get(DBTag))
+		tableTag := field.Tag.Get(TableTag)
+		if tableTag == "" {
+			tableTag = table
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		prefixTag, hasPrefix := field.Tag.Lookup(PrefixTag)
+		if fieldType.Kind() == reflect.Struct && ((field.Anonymous && dbTag == "") || hasPrefix) {
+			fields = append(fields, collectModelFields(fieldType, fieldIndex, prefix+prefixTag, tableTag)...)
+			continue
+		}
+
+		// unexported
+		if dbTag == "" || dbTag == "-" || field.PkgPath != "" {
+			continue
+		}
+		fields = append(fields, modelField{index: fieldIndex, column: prefix + dbTag, table: tableTag, options: options})
+	}
+	return fields
+}
+
+type tableNamer interface {
+	TableName() string
+}
+
+// table metadata of model type. pointerNamer is TableName of pointer receiver
+type modelTable struct {
+	pointerNamer bool
+	tag          string
+}
+
+// TableName method or table tag of blank field. ex. _ struct{} `table:"users"`
+func getModelTableName(model interface{}, t reflect.Type) string {
+	if namer, ok := model.(tableNamer); ok {
+		return namer.TableName()
+	}
+
+	table := getModelTable(t)
+	// value model is copied to pointer only when pointer receiver has TableName
+	if v := reflect.ValueOf(model); table.pointerNamer && v.Kind() == reflect.Struct {
+		ptr := reflect.New(t)
+		ptr.Elem().Set(v)
+		return ptr.Interface().(tableNamer).TableName()
+	}
+	return table.tag
+}
+
+func getModelTable(t reflect.Type) modelTable {
+	if cached, ok := modelTableCache.Load(t); ok {
+		return cached.(modelTable)
+	}
+
+	table := modelTable{pointerNamer: reflect.PtrTo(t).Implements(tableNamerType)}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Name == "_" && field.Tag.Get(TableTag) != "" {
+			table.tag = field.Tag.Get(TableTag)
+			break
+		}
+	}
+	modelTableCache.Store(t, table)
+	return table
+}
+
+// field of search struct. index is field index in struct.
+// nested is struct field without db tag, which becomes sub group.
+type searchField struct {
+	index    int
+	nested   bool
+	column   string
+	bind     string
+	operator string
+	group    string
+	logic    string
+}
+
+type searchFieldsKey struct {
+	t         reflect.Type
+	targetTag string
+}
+
+// fields which have db, target and operator tags, and nested fields
+func getSearchFields(t reflect.Type, targetTag string) []searchField {
+	key := searchFieldsKey{t: t, targetTag: targetTag}
+	if cached, ok := searchFieldsCache.Load(key); ok {
+		return cached.([]searchField)
+	}
+
+	fields := collectSearchFields(t, targetTag)
+	searchFieldsCache.Store(key, fields)
+	return fields
+}
+
+func collectSearchFields(t reflect.Type, targetTag string) []searchField {
+	fields := make([]searchField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		dbTag := field.Tag.Get(DBTag)
+		column, _ := parseDBTag(dbTag)
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		searchField := searchField{
+			index:    i,
+			nested:   dbTag == "" && fieldType.Kind() == reflect.Struct,
+			column:   column,
+			bind:     field.Tag.Get(targetTag),
+			operator: field.Tag.Get(OperatorTag),
+			group:    field.Tag.Get(GroupTag),
+			logic:    strings.ToUpper(field.Tag.Get(LogicTag)),
+		}
+		if !searchField.nested && (column == "" || column == "-" || searchField.bind == "" || searchField.operator == "") {
+			continue
+		}
+		fields = append(fields, searchField)
+	}
+	return fields
+}
+
+// ex. "id,pk,autoincr" => "id", {pk: true, autoincr: true}
+func parseDBTag(tag string) (string, map[string]bool) {
+	split := strings.Split(tag, ",")
+	options := make(map[string]bool, len(split)-1)
+	for _, option := range split[1:] {
+		options[strings.TrimSpace(option)] = true
+	}
+	return strings.TrimSpace(split[0]), options
+}
+
+// false when embedded pointer on the way is nil
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
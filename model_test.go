@@ -0,0 +1,152 @@
+package query_builder
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type benchmarkBaseModel struct {
+	ID        int       `db:"id,pk,autoincr"`
+	CreatedAt time.Time `db:"created_at,readonly"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type benchmarkUser struct {
+	benchmarkBaseModel `table:"users"`
+	Name               string `db:"name" table:"users"`
+	Email              string `db:"email" table:"users"`
+	Age                int    `db:"age" table:"users"`
+	Sex                string `db:"sex" table:"users"`
+}
+
+type benchmarkSearchUsersParameter struct {
+	Status  *string  `db:"status" search:"status" operator:"eq"`
+	UserIDs []int    `db:"user_id" search:"user_ids" operator:"in"`
+	Name    *string  `db:"name" search:"keyword_name" operator:"contains" group:"keyword" logic:"or"`
+	Email   *string  `db:"email" search:"keyword_email" operator:"contains" group:"keyword"`
+	Age     *[2]int  `db:"age" search:"age_range" operator:"between"`
+	Sex     *string  `db:"sex" search:"sex" operator:"ne"`
+	Deleted *bool    `db:"deleted_at" search:"deleted" operator:"is-null"`
+	Others  struct{} `logic:"or"`
+}
+
+// UpdatedAt shadows updated_at of benchmarkBaseModel
+type shadowedUser struct {
+	benchmarkUser
+	UpdatedAt *time.Time `db:"updated_at" table:"users"`
+}
+
+func Test_getModelFields_Concurrent(t *testing.T) {
+	typ := reflect.TypeOf(shadowedUser{})
+	expected := visibleModelFields(collectModelFields(typ, nil, "", ""))
+	if len(expected) == len(collectModelFields(typ, nil, "", "")) {
+		t.Fatal("fixture should have shadowed field")
+	}
+	for _, field := range expected {
+		if field.column == "updated_at" && !reflect.DeepEqual([]int{1}, field.index) {
+			t.Fatalf("updated_at should be field of shadowedUser. actual index: %v", field.index)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if actual := getModelFields(typ); !reflect.DeepEqual(expected, actual) {
+				t.Errorf("expected: %v, actual: %v", expected, actual)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func newBenchmarkSearchParameter() benchmarkSearchUsersParameter {
+	status, keyword, sex, deleted := "active", "tre", "unknown", false
+	return benchmarkSearchUsersParameter{
+		Status:  &status,
+		UserIDs: []int{1, 2, 3},
+		Name:    &keyword,
+		Email:   &keyword,
+		Age:     &[2]int{20, 30},
+		Sex:     &sex,
+		Deleted: &deleted,
+	}
+}
+
+// baseline without cache
+func Benchmark_collectModelFields(b *testing.B) {
+	typ := reflect.TypeOf(benchmarkUser{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		collectModelFields(typ, nil, "", "")
+	}
+}
+
+func Benchmark_getModelFields(b *testing.B) {
+	typ := reflect.TypeOf(benchmarkUser{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		getModelFields(typ)
+	}
+}
+
+// baseline without cache
+func Benchmark_collectSearchFields(b *testing.B) {
+	typ := reflect.TypeOf(benchmarkSearchUsersParameter{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		collectSearchFields(typ, SearchTag)
+	}
+}
+
+func Benchmark_getSearchFields(b *testing.B) {
+	typ := reflect.TypeOf(benchmarkSearchUsersParameter{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		getSearchFields(typ, SearchTag)
+	}
+}
+
+// value model without TableName is not copied
+func Benchmark_getModelTableName(b *testing.B) {
+	user := benchmarkUser{Name: "trewanek"}
+	typ := reflect.TypeOf(user)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		getModelTableName(user, typ)
+	}
+}
+
+func Benchmark_SelectQueryBuilder_Model(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewSelectQueryBuilder().Table("users").Model(benchmarkUser{}).Build()
+	}
+}
+
+func Benchmark_UpdateQueryBuilder_Model(b *testing.B) {
+	user := benchmarkUser{benchmarkBaseModel: benchmarkBaseModel{ID: 1}, Name: "trewanek", Age: 20}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewUpdateQueryBuilder().Table("users").Model(user).Build()
+	}
+}
+
+func Benchmark_SelectQueryBuilder_WhereMultiByStruct(b *testing.B) {
+	searchParam := newBenchmarkSearchParameter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewSelectQueryBuilder().Table("users").WhereMultiByStruct(searchParam).Build()
+	}
+}
+
+func Benchmark_SelectQueryBuilder_BuildWithArgs(b *testing.B) {
+	searchParam := newBenchmarkSearchParameter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewSelectQueryBuilder().Table("users").WhereMultiByStruct(searchParam).BuildWithArgs()
+	}
+}
@@ -0,0 +1,49 @@
+package query_builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+var OptimisticLockConflictErr = fmt.Errorf("row is updated or deleted by another transaction")
+
+// *sql.DB, *sql.Tx, *sql.Conn, *sqlx.DB etc.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// no row is affected by update with optimistic lock. errors.Is(err, OptimisticLockConflictErr) is true.
+type ConflictError struct {
+	Table   string
+	Version interface{}
+}
+
+func (err *ConflictError) Error() string {
+	return fmt.Sprintf("%v: table %s, version %v", OptimisticLockConflictErr, err.Table, err.Version)
+}
+
+func (err *ConflictError) Unwrap() error {
+	return OptimisticLockConflictErr
+}
+
+func (builder *queryBuilder) optimisticLock(column string) *queryBuilder {
+	copied := builder.copy()
+	copied.versionColumn = column
+	return copied
+}
+
+// SET {version} = {version} + 1 WHERE ... AND {version} = :{version}
+func (builder *queryBuilder) versioned() *queryBuilder {
+	if builder.versionColumn == "" {
+		return builder
+	}
+
+	copied := builder.where(builder.versionColumn, Equal)
+	if !containsString(builder.columns, builder.versionColumn) {
+		copied.columns = append(append(make([]string, 0, len(builder.columns)+1), builder.columns...), builder.versionColumn)
+	}
+	copied.expressions = copyStringMap(builder.expressions)
+	copied.expressions[builder.versionColumn] = builder.quoteIdentifier(builder.versionColumn) + " + 1"
+	return copied
+}
@@ -0,0 +1,99 @@
+package query_builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type fakeResult struct {
+	affected int64
+}
+
+func (result fakeResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (result fakeResult) RowsAffected() (int64, error) {
+	return result.affected, nil
+}
+
+type fakeExecer struct {
+	affected int64
+	query    string
+	args     []interface{}
+}
+
+func (execer *fakeExecer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	execer.query, execer.args = query, args
+	return fakeResult{affected: execer.affected}, nil
+}
+
+func Test_UpdateQueryBuilder_OptimisticLock(t *testing.T) {
+	type Article struct {
+		ID      int    `db:"id,pk" table:"articles"`
+		Title   string `db:"title" table:"articles"`
+		Version int    `db:"version,version" table:"articles"`
+	}
+
+	testCommonFunc(
+		t,
+		"UPDATE articles SET title = :title, version = version + 1 WHERE id = :id AND version = :version;",
+		NewUpdateQueryBuilder().
+			Placeholder(Named).
+			Table("articles").
+			Model(Article{ID: 1, Title: "title", Version: 3}).
+			Build(),
+		false,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE `articles` SET `title` = $1, `version` = `version` + 1 WHERE `id` = $2 AND `version` = $3;",
+		NewUpdateQueryBuilder().
+			Placeholder(DollarNumber).
+			Quote(QuoteAlways).
+			Table("articles").
+			Column("title", "version").
+			Where("id", Equal).
+			OptimisticLock("version").
+			Build(),
+		false,
+	)
+
+	builder := NewUpdateQueryBuilder().
+		Placeholder(Named).
+		Table("articles").
+		Model(Article{ID: 1, Title: "title", Version: 3})
+
+	execer := &fakeExecer{affected: 1}
+	if _, err := builder.ExecContext(context.Background(), execer, Article{ID: 1, Title: "title", Version: 3}); err != nil {
+		t.Fatal(err)
+	}
+	expectedQuery := "UPDATE articles SET title = ?, version = version + 1 WHERE id = ? AND version = ?;"
+	if execer.query != expectedQuery {
+		t.Logf("\nexpected: %s\nactual  : %s", expectedQuery, execer.query)
+		t.Fail()
+	}
+	if expectedArgs := []interface{}{"title", 1, 3}; !reflect.DeepEqual(expectedArgs, execer.args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, execer.args)
+		t.Fail()
+	}
+
+	_, err := builder.ExecContext(context.Background(), &fakeExecer{affected: 0}, Article{ID: 1, Title: "title", Version: 3})
+	var conflictErr *ConflictError
+	if !errors.Is(err, OptimisticLockConflictErr) || !errors.As(err, &conflictErr) || conflictErr.Version != 3 {
+		t.Logf("expected: %v, actual: %v", OptimisticLockConflictErr, err)
+		t.Fail()
+	}
+
+	if _, err := NewUpdateQueryBuilder().
+		Table("articles").
+		Column("title").
+		ExecContext(context.Background(), &fakeExecer{affected: 0}, map[string]interface{}{"title": "title"}); err != nil {
+		t.Logf("expected: nil, actual: %v", err)
+		t.Fail()
+	}
+}
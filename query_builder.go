@@ -1,6 +1,7 @@
 package query_builder
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -11,6 +12,19 @@ var (
 	SubQueryEmptyErr           = fmt.Errorf("subQuery is required. this should be not empty")
 	SubQueryReturnMultiRowsErr = fmt.Errorf("subQuery returns multi rows. set limit and specify single row")
 	UnspecifiedColumnErr       = fmt.Errorf("subQuery column should be specified")
+	ColumnCountMismatchErr     = fmt.Errorf("insert columns and select columns need to be same length")
+	UnsupportedLockErr         = fmt.Errorf("locking clause is not supported by this dialect")
+	LockStrengthRequiredErr    = fmt.Errorf("lock strength is required. call ForUpdate, ForShare or ForNoKeyUpdate")
+	LockWaitPolicyConflictErr  = fmt.Errorf("NoWait and SkipLocked can not be used together")
+	BetweenValueErr            = fmt.Errorf("between value should be slice or array of 2 elements")
+	CaseWhenRequiredErr        = fmt.Errorf("case expression requires at least one when")
+	CaseConditionRequiredErr   = fmt.Errorf("when of case expression requires condition")
+	InvalidIdentifierErr       = fmt.Errorf("identifier is invalid. use Raw for expression")
+	SuspiciousExpressionErr    = fmt.Errorf("expression contains suspicious token")
+	InValuesErr                = fmt.Errorf("in values should be slice or array")
+	UnsupportedArrayBindingErr = fmt.Errorf("array binding is supported only by PostgreSQL")
+	ModelColumnNotFoundErr     = fmt.Errorf("model has no column of table. set table tag, TableName method or Table")
+	ModelJoinOrderErr          = fmt.Errorf("columns of joined table are skipped by model. call Model after Join")
 )
 
 type queryBuilder struct {
@@ -18,15 +32,32 @@ type queryBuilder struct {
 	tableName       string
 	columns         []string
 	whereConditions []map[string]string
+	cases           map[int]*CaseExpression
 	placeholderType int
+	dialect         int
+	quoteMode       int
+	strict          bool
+	rawColumns      map[int]bool
+	windowColumns   map[int]*Window
+	columnAliases   map[int]string
+	values          map[string]interface{}
+	expressions     map[string]string
+	audit           *AuditColumns
+	defaultAudit    bool
+	actor           interface{}
+	versionColumn   string
+	modelSkipped    []string
+	trashColumn     string
+	trashed         int
+	scopes          []Scope
+	unscoped        map[string]bool
+	arrayBinding    bool
+	subQueries      []*SelectQueryBuilder
 	argNum          int
-	ignoreZeroValue bool
 }
 
 func newQueryBuilder() *queryBuilder {
-	return &queryBuilder{
-		ignoreZeroValue: true,
-	}
+	return &queryBuilder{}
 }
 
 func (builder *queryBuilder) placeholder(placeholderType int) *queryBuilder {
@@ -35,6 +66,30 @@ func (builder *queryBuilder) placeholder(placeholderType int) *queryBuilder {
 	return copied
 }
 
+func (builder *queryBuilder) setDialect(dialect int) *queryBuilder {
+	copied := builder.copy()
+	copied.dialect = dialect
+	return copied
+}
+
+func (builder *queryBuilder) quote(quoteMode int) *queryBuilder {
+	copied := builder.copy()
+	copied.quoteMode = quoteMode
+	return copied
+}
+
+func (builder *queryBuilder) setStrict() *queryBuilder {
+	copied := builder.copy()
+	copied.strict = true
+	return copied
+}
+
+func (builder *queryBuilder) setArrayBinding() *queryBuilder {
+	copied := builder.copy()
+	copied.arrayBinding = true
+	return copied
+}
+
 func (builder *queryBuilder) table(tableName string) *queryBuilder {
 	copied := builder.copy()
 	copied.tableName = tableName
@@ -43,12 +98,45 @@ func (builder *queryBuilder) table(tableName string) *queryBuilder {
 
 func (builder *queryBuilder) column(columns ...string) *queryBuilder {
 	copied := builder.copy()
-	for _, column := range columns {
-		copied.columns = append(copied.columns, column)
+	copied.columns = append(copied.columns, columns...)
+	return copied
+}
+
+// expression of Raw is remembered by index of columns
+func (builder *queryBuilder) rawColumn(expressions ...RawExpr) *queryBuilder {
+	copied := builder.copy()
+	copied.rawColumns = copyRawColumns(copied.rawColumns)
+	for _, expression := range expressions {
+		copied.rawColumns[len(copied.columns)] = true
+		copied.columns = append(copied.columns, expression.expression)
 	}
 	return copied
 }
 
+// column is alias of window, which is remembered by index of columns
+func (builder *queryBuilder) windowColumn(window *Window, alias string) *queryBuilder {
+	copied := builder.copy()
+	copied.windowColumns = make(map[int]*Window, len(builder.windowColumns)+1)
+	for index, w := range builder.windowColumns {
+		copied.windowColumns[index] = w
+	}
+	copied.windowColumns[len(copied.columns)] = window
+	copied.columns = append(copied.columns, alias)
+	return copied
+}
+
+// column is column for SET, alias for SELECT. expression is remembered by index of columns
+func (builder *queryBuilder) caseColumn(column string, expression *CaseExpression) *queryBuilder {
+	copied := builder.copy()
+	copied.cases = make(map[int]*CaseExpression, len(builder.cases)+1)
+	for index, value := range builder.cases {
+		copied.cases[index] = value
+	}
+	copied.cases[len(copied.columns)] = expression
+	copied.columns = append(copied.columns, column)
+	return copied
+}
+
 func (builder *queryBuilder) omit(targets ...string) *queryBuilder {
 	copied := builder.copy()
 	dic := make([]string, 0, len(copied.columns))
@@ -68,22 +156,45 @@ func (builder *queryBuilder) omit(targets ...string) *queryBuilder {
 	}
 
 	sorted := make([]string, 0, len(copied.columns)-len(targets))
-	for _, column := range dic {
+	rawColumns := make(map[int]bool, len(copied.rawColumns))
+	windowColumns := make(map[int]*Window, len(copied.windowColumns))
+	cases := make(map[int]*CaseExpression, len(copied.cases))
+	columnAliases := make(map[int]string, len(copied.columnAliases))
+	for index, column := range dic {
 		if m[column] == nil {
 			continue
 		}
+		if copied.rawColumns[index] {
+			rawColumns[len(sorted)] = true
+		}
+		if window := copied.windowColumns[index]; window != nil {
+			windowColumns[len(sorted)] = window
+		}
+		if expression := copied.cases[index]; expression != nil {
+			cases[len(sorted)] = expression
+		}
+		if alias, ok := copied.columnAliases[index]; ok {
+			columnAliases[len(sorted)] = alias
+		}
 		sorted = append(sorted, *m[column])
 	}
 
 	copied.columns = sorted
+	copied.rawColumns = rawColumns
+	copied.windowColumns = windowColumns
+	copied.cases = cases
+	copied.columnAliases = columnAliases
 	return copied
 }
 
 // db・tableタグを見て、FieldをSelect対象としてSet
-func (builder *queryBuilder) model(model interface{}, notIgnoreZeroValue ...bool) *queryBuilder {
-	if notIgnoreZeroValue != nil && notIgnoreZeroValue[0] {
-		builder.ignoreZeroValue = false
-	}
+// insert skips autoincr and readonly columns, update skips pk, autoincr and readonly columns from SET and adds WHERE pk = ?
+// version column of update is used for optimistic lock.
+// zero value is ignored by default or by omitempty option, default option is ignored on insert when zero value.
+// always option writes zero value(nil pointer is NULL) without notIgnoreZeroValue, and takes priority over omitempty and default.
+// columns of joinTables are qualified by table. ex. orders.total
+func (builder *queryBuilder) model(model interface{}, usage int, joinTables []string, notIgnoreZeroValue ...bool) *queryBuilder {
+	ignoreZeroValue := notIgnoreZeroValue == nil || !notIgnoreZeroValue[0]
 
 	copied := builder.copy()
 	t := reflect.TypeOf(model)
@@ -101,65 +212,143 @@ func (builder *queryBuilder) model(model interface{}, notIgnoreZeroValue ...bool
 		panic("model should be struct")
 	}
 
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		fieldValue := v.Field(i)
-		dbTag, tableTag := field.Tag.Get(DBTag), field.Tag.Get(TableTag)
+	// table of model is used when Table is not called, and is default of table tag
+	modelTable := getModelTableName(model, t)
+	if copied.tableName == "" {
+		copied.tableName = modelTable
+	}
 
-		if dbTag == "" {
-			continue
+	matched := false
+	for _, field := range getModelFields(t) {
+		table := field.table
+		if table == "" {
+			table = modelTable
 		}
+		column := field.column
+		if table != copied.tableName {
+			if !containsString(joinTables, table) {
+				// Join of this table after model panics instead of dropping columns silently
+				if !containsString(copied.modelSkipped, table) {
+					copied.modelSkipped = append(append(make([]string, 0, len(copied.modelSkipped)+1), copied.modelSkipped...), table)
+				}
+				continue
+			}
+			column = table + "." + column
+		}
+		matched = true
 
-		if tableTag != builder.tableName {
+		if usage == modelUpdate && field.options[PrimaryKeyOption] {
+			// Model can be called repeatedly without WHERE id = ? AND id = ?
+			if condition := newCondition(field.column, Equal, "AND", nil); !copied.hasCondition(condition) {
+				copied.whereConditions = append(copied.whereConditions, condition)
+			}
+			continue
+		}
+		if usage == modelUpdate && field.options[VersionOption] {
+			copied.versionColumn = field.column
+			continue
+		}
+		if usage != modelSelect && (field.options[AutoIncrementOption] || field.options[ReadOnlyOption]) {
 			continue
 		}
 
-		if fieldValue.Type().Kind() == reflect.Ptr && fieldValue.IsNil() {
+		fieldValue, found := fieldByIndex(v, field.index)
+		if !found {
 			continue
 		}
 
-		if fieldValue.Type().Kind() == reflect.Ptr {
-			fieldValue = fieldValue.Elem()
+		// non-nil pointer is set even if it points zero value. nil pointer is NULL when zero value is not ignored
+		ok := !field.options[AlwaysOption] &&
+			(ignoreZeroValue || (usage != modelSelect && field.options[OmitEmptyOption]) || (usage == modelInsert && field.options[DefaultOption]))
+		if fieldValue.Kind() == reflect.Ptr {
+			if ok && fieldValue.IsNil() {
+				continue
+			}
+		} else if ok && isZeroValue(fieldValue) {
 			continue
 		}
 
-		ok := builder.ignoreZeroValue
-		if ok && fieldValue.IsZero() {
+		// SET and INSERT columns of repeated Model are not duplicated
+		if usage != modelSelect && containsString(copied.columns, column) {
 			continue
 		}
+		copied.columns = append(copied.columns, column)
+	}
 
-		copied.columns = append(copied.columns, dbTag)
+	if !matched {
+		panic(ModelColumnNotFoundErr)
 	}
 	return copied
 }
 
-func (builder *queryBuilder) where(column, operator string, bind ...string) *queryBuilder {
-	copied := builder.copy()
+func (builder *queryBuilder) hasCondition(condition map[string]string) bool {
+	for _, c := range builder.whereConditions {
+		if reflect.DeepEqual(c, condition) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, target string) bool {
+	for _, s := range list {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}
+
+// sql.NullString, sql.Null[T] etc. are zero when not Valid. driver.Valuer is zero when Value() is nil
+func isZeroValue(fieldValue reflect.Value) bool {
+	if fieldValue.Kind() == reflect.Struct {
+		if valid := fieldValue.FieldByName("Valid"); valid.IsValid() && valid.Kind() == reflect.Bool {
+			return !valid.Bool()
+		}
+	}
+
+	if fieldValue.CanInterface() {
+		if valuer, ok := fieldValue.Interface().(driver.Valuer); ok {
+			if value, err := valuer.Value(); err == nil && value == nil {
+				return true
+			}
+		}
+	}
+	return fieldValue.IsZero()
+}
+
+// bind is column when it is not passed
+func newCondition(column, operator, logical string, bind []string) map[string]string {
 	bd := column
 	if len(bind) != 0 {
 		bd = bind[0]
 	}
-	copied.whereConditions = append(copied.whereConditions, map[string]string{
+	return map[string]string{
 		"column":   column,
 		"operator": operator,
 		"bind":     bd,
-		"logical":  "AND",
-	})
+		"logical":  logical,
+	}
+}
+
+func (builder *queryBuilder) where(column, operator string, bind ...string) *queryBuilder {
+	copied := builder.copy()
+	copied.whereConditions = append(copied.whereConditions, newCondition(column, operator, "AND", bind))
 	return copied
 }
 
 func (builder *queryBuilder) or(column, operator string, bind ...string) *queryBuilder {
 	copied := builder.copy()
-	bd := column
-	if len(bind) != 0 {
-		bd = bind[0]
-	}
-	copied.whereConditions = append(copied.whereConditions, map[string]string{
-		"column":   column,
-		"operator": operator,
-		"bind":     bd,
-		"logical":  "OR",
-	})
+	copied.whereConditions = append(copied.whereConditions, newCondition(column, operator, "OR", bind))
+	return copied
+}
+
+// expression of Raw is rendered as it is instead of column
+func (builder *queryBuilder) whereRaw(logical string, expression RawExpr, operator string, bind ...string) *queryBuilder {
+	copied := builder.copy()
+	condition := newCondition(expression.expression, operator, logical, bind)
+	condition["raw"] = "true"
+	copied.whereConditions = append(copied.whereConditions, condition)
 	return copied
 }
 
@@ -167,45 +356,84 @@ func (builder *queryBuilder) or(column, operator string, bind ...string) *queryB
 // use in Operator and Placeholder, if bind passed, IN(:{bind}1, :{bind}2, :{bind}3...})
 func (builder *queryBuilder) whereIn(column string, listLength int, bind ...string) *queryBuilder {
 	copied := builder.copy()
-	bd := column
-	if len(bind) != 0 {
-		bd = bind[0]
-	}
-	copied.whereConditions = append(copied.whereConditions, map[string]string{
-		"column":     column,
-		"listLength": strconv.Itoa(listLength),
-		"operator":   In,
-		"bind":       bd,
-		"logical":    "AND",
-	})
+	condition := newCondition(column, In, "AND", bind)
+	condition["listLength"] = strconv.Itoa(listLength)
+	copied.whereConditions = append(copied.whereConditions, condition)
 	return copied
 }
 
 func (builder *queryBuilder) whereNotIn(column string, listLength int, bind ...string) *queryBuilder {
 	copied := builder.copy()
-	bd := column
-	if len(bind) != 0 {
-		bd = bind[0]
+	condition := newCondition(column, NotIn, "AND", bind)
+	condition["listLength"] = strconv.Itoa(listLength)
+	copied.whereConditions = append(copied.whereConditions, condition)
+	return copied
+}
+
+// length of IN list is taken from values. values are kept by bind for Args.
+func (builder *queryBuilder) whereInValues(operator, column string, values interface{}, bind ...string) *queryBuilder {
+	list := reflect.Indirect(reflect.ValueOf(values))
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		panic(InValuesErr)
+	}
+
+	var copied *queryBuilder
+	if operator == NotIn {
+		copied = builder.whereNotIn(column, list.Len(), bind...)
+	} else {
+		copied = builder.whereIn(column, list.Len(), bind...)
+	}
+
+	condition := copied.whereConditions[len(copied.whereConditions)-1]
+	condition["values"] = "true"
+
+	copied.values = make(map[string]interface{}, len(builder.values)+1)
+	for name, value := range builder.values {
+		copied.values[name] = value
 	}
-	copied.whereConditions = append(copied.whereConditions, map[string]string{
-		"column":     column,
-		"listLength": strconv.Itoa(listLength),
-		"operator":   NotIn,
-		"bind":       bd,
-		"logical":    "AND",
-	})
+	copied.values[condition["bind"]] = values
 	return copied
 }
 
+// values of search struct are kept by bind for Args. like values are wrapped by LikePattern
 func (builder *queryBuilder) whereMultiByStruct(targetTag string, src interface{}) *queryBuilder {
 	copied := builder.copy()
+	copied.values = make(map[string]interface{}, len(builder.values))
+	for name, value := range builder.values {
+		copied.values[name] = value
+	}
+
+	_, v := builder.getReflectTypeAndValue(src)
 	searchMap := builder.buildBindMap(targetTag, src)
 	for _, info := range searchMap {
 		op := getOperatorFromTag(info["operator"])
-		if op == "" {
+		switch op {
+		case "":
 			continue
+		case In:
+			listLength, _ := strconv.Atoi(info["listLength"])
+			copied = copied.whereIn(info["target"], listLength, info["bind"])
+		case NotIn:
+			listLength, _ := strconv.Atoi(info["listLength"])
+			copied = copied.whereNotIn(info["target"], listLength, info["bind"])
+		default:
+			copied = copied.where(info["target"], op, info["bind"])
+		}
+
+		condition := copied.whereConditions[len(copied.whereConditions)-1]
+		for _, key := range []string{"logical", "open", "close"} {
+			if info[key] != "" {
+				condition[key] = info[key]
+			}
+		}
+		if isLikePatternTag(info["operator"]) {
+			condition["escape"] = "true"
+		}
+
+		names, values := getBindValues(v, info)
+		for index, name := range names {
+			copied.values[name] = values[index]
 		}
-		copied = copied.where(info["target"], op, info["bind"])
 	}
 	return copied
 }
@@ -225,74 +453,170 @@ func (builder *queryBuilder) whereSubQuery(column, operator string, subQueryBuil
 		panic(SubQueryReturnMultiRowsErr)
 	}
 
-	copied.whereConditions = append(copied.whereConditions, map[string]string{
-		"column":   column,
-		"operator": operator,
-		"subQuery": strings.TrimRight(subQueryBuilder.Build(), ";"),
-		"logical":  "AND",
-	})
+	// subquery is kept as builder and built with outer query. ex. scopes, placeholder numbers and args
+	condition := newCondition(column, operator, "AND", nil)
+	delete(condition, "bind")
+	condition["subQuery"] = strconv.Itoa(len(builder.subQueries))
+	copied.whereConditions = append(copied.whereConditions, condition)
+	copied.subQueries = append(append(make([]*SelectQueryBuilder, 0, len(builder.subQueries)+1), builder.subQueries...), subQueryBuilder)
 	return copied
 }
 
+// subquery is rendered by placeholder of outer query and numbered through whole query.
+// Unscoped of outer query is applied to subquery too.
+func (builder *queryBuilder) getSubQueryParagraph(index string) string {
+	i, _ := strconv.Atoi(index)
+	sub := builder.subQueries[i].Placeholder(builder.placeholderType)
+	if len(builder.unscoped) > 0 {
+		names := make([]string, 0, len(builder.unscoped))
+		for name := range builder.unscoped {
+			names = append(names, name)
+		}
+		sub = sub.Unscoped(names...)
+	}
+
+	var paragraph string
+	paragraph, builder.argNum = sub.build(builder.argNum)
+	return paragraph
+}
+
 func (builder *queryBuilder) copy() *queryBuilder {
 	return &queryBuilder{
 		query:           builder.query,
 		tableName:       builder.tableName,
 		columns:         builder.columns,
 		whereConditions: builder.whereConditions,
+		cases:           builder.cases,
 		placeholderType: builder.placeholderType,
-		ignoreZeroValue: builder.ignoreZeroValue,
+		dialect:         builder.dialect,
+		quoteMode:       builder.quoteMode,
+		strict:          builder.strict,
+		rawColumns:      builder.rawColumns,
+		windowColumns:   builder.windowColumns,
+		columnAliases:   builder.columnAliases,
+		values:          builder.values,
+		expressions:     builder.expressions,
+		audit:           builder.audit,
+		defaultAudit:    builder.defaultAudit,
+		actor:           builder.actor,
+		versionColumn:   builder.versionColumn,
+		modelSkipped:    builder.modelSkipped,
+		trashColumn:     builder.trashColumn,
+		trashed:         builder.trashed,
+		scopes:          builder.scopes,
+		unscoped:        builder.unscoped,
+		arrayBinding:    builder.arrayBinding,
+		subQueries:      builder.subQueries,
 	}
 }
 
-func (builder *queryBuilder) getWhereParagraphs() []string {
-	paragraphs := make([]string, 0, 0)
-	//paragraphs = append(paragraphs, "WHERE")
-
-	for index, condition := range builder.whereConditions {
-		op := condition["operator"]
-		bind := "?"
-		if builder.placeholderType == Named {
-			bind = ":" + condition["bind"]
-		}
-		if builder.placeholderType == DollarNumber {
-			bind = "$"
-			if op != In && op != NotIn {
-				bind += strconv.Itoa(builder.argNum + 1)
-				builder.argNum += 1
-			}
+// SET {column} = {bind}, CASE expression or expression of audit and optimistic lock
+func (builder *queryBuilder) getSetParagraphs(columns ...string) string {
+	setContents := make([]string, 0, len(columns))
+	format := "%s = %s"
+	for index, column := range columns {
+		if expression := builder.cases[index]; expression != nil {
+			setContents = append(setContents, fmt.Sprintf(format, builder.quoteIdentifier(column), expression.build(builder)))
+			continue
 		}
-
-		if index == 0 {
-			condition["logical"] = "WHERE"
+		if expression, ok := builder.expressions[column]; ok {
+			setContents = append(setContents, fmt.Sprintf(format, builder.quoteIdentifier(column), expression))
+			continue
 		}
+		setContents = append(setContents, fmt.Sprintf(format, builder.quoteIdentifier(column), builder.getBind(column)))
+	}
+	return fmt.Sprintf("SET %s", strings.Join(setContents, ", "))
+}
 
-		paragraph := builder.getWhereParagraph(condition, bind)
+func (builder *queryBuilder) getWhereParagraphs() []string {
+	return builder.getConditionParagraphs("WHERE", builder.whereConditions)
+}
 
-		paragraphs = append(paragraphs, paragraph)
+// first condition's logical is replaced by first. ex. WHERE, WHEN
+func (builder *queryBuilder) getConditionParagraphs(first string, conditions []map[string]string) []string {
+	paragraphs := make([]string, 0, len(conditions))
+	for index, condition := range conditions {
+		logical := condition["logical"]
+		if index == 0 {
+			logical = first
+		}
+		paragraphs = append(paragraphs, builder.getWhereParagraph(logical, condition))
 	}
 	return paragraphs
 }
 
-func (builder *queryBuilder) getWhereParagraph(condition map[string]string, bind string) string {
-	logical := condition["logical"]
-	baseFormat := logical + " %s %s %s"
+func (builder *queryBuilder) getWhereParagraph(logical string, condition map[string]string) string {
+	return logical + " " + condition["open"] + builder.getConditionParagraph(condition) + condition["close"]
+}
+
+func (builder *queryBuilder) getConditionParagraph(condition map[string]string) string {
+	baseFormat := "%s %s %s"
 	column := condition["column"]
+	if condition["raw"] == "" && !functionRegexp.MatchString(column) {
+		column = builder.quoteIdentifier(column)
+	}
 	op := condition["operator"]
 	sub := condition["subQuery"]
 
 	if sub != "" {
-		return fmt.Sprintf("%s %s %s (%s)", logical, column, op, sub)
+		return fmt.Sprintf("%s %s (%s)", column, op, builder.getSubQueryParagraph(sub))
 	}
 
 	switch op {
 	case IsNull, IsNotNull:
-		return fmt.Sprintf("%s %s %s", logical, column, op)
+		return fmt.Sprintf("%s %s", column, op)
 	case In, NotIn:
 		listLength, _ := strconv.Atoi(condition["listLength"])
-		return fmt.Sprintf(baseFormat, column, op, builder.buildListBind(bind, listLength))
+		switch {
+		case condition["values"] != "" && builder.arrayBinding:
+			// = ANY(:{bind}) | <> ALL(:{bind})
+			if builder.dialect != PostgreSQL {
+				panic(UnsupportedArrayBindingErr)
+			}
+			format := "%s = ANY(%s)"
+			if op == NotIn {
+				format = "%s <> ALL(%s)"
+			}
+			return fmt.Sprintf(format, column, builder.getBind(condition["bind"]))
+		case listLength == 0:
+			// IN () is invalid. nothing matches IN, everything matches NOT IN
+			if op == NotIn {
+				return "1=1"
+			}
+			return "1=0"
+		}
+		return fmt.Sprintf(baseFormat, column, op, builder.buildListBind(condition["bind"], listLength))
+	case Between:
+		// BETWEEN :{bind}1 AND :{bind}2
+		from, to := builder.getBind(condition["bind"]+"1"), builder.getBind(condition["bind"]+"2")
+		return fmt.Sprintf(baseFormat, column, op, from+" AND "+to)
 	default:
-		return fmt.Sprintf(baseFormat, column, op, bind)
+		paragraph := fmt.Sprintf(baseFormat, column, op, builder.getBind(condition["bind"]))
+		if condition["escape"] != "" {
+			paragraph += " ESCAPE " + builder.getLikeEscape()
+		}
+		return paragraph
+	}
+}
+
+// escape character of LikePattern. backslash is doubled in MySQL string literal
+func (builder *queryBuilder) getLikeEscape() string {
+	if builder.dialect == MySQL {
+		return `'\\'`
+	}
+	return `'\'`
+}
+
+// ? | :{bind} | ${argNum}
+func (builder *queryBuilder) getBind(bind string) string {
+	switch builder.placeholderType {
+	case Named:
+		return ":" + bind
+	case DollarNumber:
+		builder.argNum += 1
+		return "$" + strconv.Itoa(builder.argNum)
+	default:
+		return "?"
 	}
 }
 
@@ -300,16 +624,7 @@ func (builder *queryBuilder) buildListBind(bind string, listLength int) string {
 	format := "(%s)"
 	list := make([]string, 0, listLength)
 	for i := 0; i < listLength; i++ {
-		if builder.placeholderType == Named {
-			list = append(list, bind+strconv.Itoa(i+1))
-			continue
-		}
-		if builder.placeholderType == DollarNumber {
-			list = append(list, "$"+strconv.Itoa(builder.argNum+1))
-			builder.argNum += 1
-			continue
-		}
-		list = append(list, bind)
+		list = append(list, builder.getBind(bind+strconv.Itoa(i+1)))
 	}
 	return fmt.Sprintf(format, strings.Join(list, ", "))
 }
@@ -336,42 +651,264 @@ func getOperatorFromTag(tag string) string {
 		return IsNull
 	case "not-null":
 		return IsNotNull
+	case "in":
+		return In
+	case "not-in":
+		return NotIn
+	case "between":
+		return Between
+	case "prefix", "suffix", "contains":
+		return Like
 	default:
 		return ""
 	}
 }
 
 func (builder *queryBuilder) buildBindMap(targetTag string, src interface{}) []map[string]string {
-	t, v := builder.getReflectTypeAndValue(src)
-	bindMap := make(map[string]map[string]string)
-	dic := make([]string, 0, t.NumField())
+	_, v := builder.getReflectTypeAndValue(src)
+	return builder.collectBindInfos(targetTag, v, "AND", nil)
+}
+
+// values of bind map in placeholder order. slice of in, not-in and between is expanded.
+func (builder *queryBuilder) buildBindArgs(targetTag string, src interface{}) ([]interface{}, map[string]interface{}) {
+	_, v := builder.getReflectTypeAndValue(src)
+	args := make([]interface{}, 0)
+	namedArgs := make(map[string]interface{})
+
+	for _, info := range builder.buildBindMap(targetTag, src) {
+		names, values := getBindValues(v, info)
+		for index, name := range names {
+			args = append(args, values[index])
+			namedArgs[name] = values[index]
+		}
+	}
+	return args, namedArgs
+}
+
+// binds and values of bind info in placeholder order. ex. {bind}1, {bind}2 of in, not-in and between
+func getBindValues(v reflect.Value, info map[string]string) ([]string, []interface{}) {
+	fieldValue := reflect.Indirect(v.FieldByIndex(parseFieldIndex(info["index"])))
+	switch getOperatorFromTag(info["operator"]) {
+	case "", IsNull, IsNotNull:
+		return nil, nil
+	case In, NotIn, Between:
+		names := make([]string, 0, fieldValue.Len())
+		values := make([]interface{}, 0, fieldValue.Len())
+		for i := 0; i < fieldValue.Len(); i++ {
+			names = append(names, info["bind"]+strconv.Itoa(i+1))
+			values = append(values, reflect.Indirect(fieldValue.Index(i)).Interface())
+		}
+		return names, values
+	}
+
+	value := fieldValue.Interface()
+	if isLikePatternTag(info["operator"]) {
+		value = LikePattern(info["operator"], fieldValue.String())
+	}
+	return []string{info["bind"]}, []interface{}{value}
+}
+
+// fields which have same group tag and nested struct fields become parenthesized group.
+// ex. group:"keyword" logic:"or" => (name LIKE :name OR email LIKE :email)
+// logical of info is connection to previous info, open and close are parentheses.
+// index of info is field index path from root struct. ex. "2.0"
+// repeated bind tag in same struct keeps position of first field and condition of last field.
+// nested structs are not merged, because same struct type can be nested twice.
+func (builder *queryBuilder) collectBindInfos(targetTag string, v reflect.Value, logical string, index []int) []map[string]string {
+	t := v.Type()
+	binds := make(map[string]map[string]string)
+	units := make([][][]map[string]string, 0, t.NumField())
+	groupIndexes := make(map[string]int)
+	groupLogicals := make(map[int]string)
+
+	for _, field := range getSearchFields(t, targetTag) {
+		fieldValue := v.Field(field.index)
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), field.index)
 
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		fieldValue := v.Field(i)
+		if fieldValue.Type().Kind() == reflect.Ptr && fieldValue.IsNil() {
+			continue
+		}
+
+		groupTag, logicTag := field.group, field.logic
+
+		var infos []map[string]string
+		if field.nested {
+			nestedLogical := "AND"
+			if logicTag != "" && groupTag == "" {
+				nestedLogical = logicTag
+			}
+			infos = wrapBindInfos(builder.collectBindInfos(targetTag, reflect.Indirect(fieldValue), nestedLogical, fieldIndex))
+		} else if info := builder.getBindInfo(field, fieldValue); info != nil {
+			info["index"] = formatFieldIndex(fieldIndex)
+			if previous := binds[field.bind]; previous != nil {
+				for key := range previous {
+					delete(previous, key)
+				}
+				for key, value := range info {
+					previous[key] = value
+				}
+				continue
+			}
+			binds[field.bind] = info
+			infos = []map[string]string{info}
+		}
 
-		if fieldValue.Type().Kind() == reflect.Ptr && v.Field(i).IsNil() {
+		if len(infos) == 0 {
 			continue
 		}
 
-		dbTag, bindTag, operatorTag := field.Tag.Get(DBTag), field.Tag.Get(targetTag), field.Tag.Get(OperatorTag)
-		if dbTag == "" || bindTag == "" || operatorTag == "" {
+		if groupTag == "" {
+			units = append(units, [][]map[string]string{infos})
 			continue
 		}
 
-		dic = append(dic, bindTag)
-		bindMap[bindTag] = map[string]string{
-			"target":   dbTag,
-			"operator": operatorTag,
+		index, ok := groupIndexes[groupTag]
+		if !ok {
+			index = len(units)
+			groupIndexes[groupTag] = index
+			units = append(units, nil)
+		}
+		if groupLogicals[index] == "" {
+			groupLogicals[index] = logicTag
 		}
+		units[index] = append(units[index], infos)
 	}
 
-	sortedByFieldNumber := make([]map[string]string, 0, len(dic))
-	for _, key := range dic {
-		bindMap[key]["bind"] = key
-		sortedByFieldNumber = append(sortedByFieldNumber, bindMap[key])
+	members := make([][]map[string]string, 0, len(units))
+	for index, unit := range units {
+		if len(unit) == 1 {
+			members = append(members, unit[0])
+			continue
+		}
+		groupLogical := groupLogicals[index]
+		if groupLogical == "" {
+			groupLogical = "AND"
+		}
+		members = append(members, wrapBindInfos(joinBindInfos(unit, groupLogical)))
+	}
+	return joinBindInfos(members, logical)
+}
+
+func formatFieldIndex(index []int) string {
+	parts := make([]string, 0, len(index))
+	for _, i := range index {
+		parts = append(parts, strconv.Itoa(i))
+	}
+	return strings.Join(parts, ".")
+}
+
+func parseFieldIndex(index string) []int {
+	parts := strings.Split(index, ".")
+	parsed := make([]int, 0, len(parts))
+	for _, part := range parts {
+		i, _ := strconv.Atoi(part)
+		parsed = append(parsed, i)
+	}
+	return parsed
+}
+
+func joinBindInfos(members [][]map[string]string, logical string) []map[string]string {
+	joined := make([]map[string]string, 0, len(members))
+	for index, infos := range members {
+		if index > 0 {
+			infos[0]["logical"] = logical
+		}
+		joined = append(joined, infos...)
+	}
+	return joined
+}
+
+func wrapBindInfos(infos []map[string]string) []map[string]string {
+	if len(infos) > 1 {
+		infos[0]["open"] = "(" + infos[0]["open"]
+		infos[len(infos)-1]["close"] += ")"
+	}
+	return infos
+}
+
+func (builder *queryBuilder) getBindInfo(field searchField, fieldValue reflect.Value) map[string]string {
+	operatorTag := field.operator
+	info := map[string]string{
+		"target":   field.column,
+		"operator": operatorTag,
+		"bind":     field.bind,
+	}
+
+	switch getOperatorFromTag(operatorTag) {
+	case IsNull, IsNotNull:
+		// bool field decides predicate. true => as tagged, false => opposite
+		if value := reflect.Indirect(fieldValue); value.Kind() == reflect.Bool && !value.Bool() {
+			info["operator"] = map[string]string{"is-null": "not-null", "not-null": "is-null"}[operatorTag]
+		}
+		delete(info, "bind")
+	case In, NotIn, Between:
+		listLength := builder.getListLength(fieldValue)
+		// empty list means no condition
+		if listLength == 0 {
+			return nil
+		}
+		// between needs from and to. it is skipped same as empty list
+		if getOperatorFromTag(operatorTag) == Between && listLength != 2 {
+			return nil
+		}
+		info["listLength"] = strconv.Itoa(listLength)
+	}
+
+	// value is escaped and wrapped by % as string
+	if isLikePatternTag(operatorTag) && reflect.Indirect(fieldValue).Kind() != reflect.String {
+		panic(fmt.Sprintf("%s operator field should be string", fieldValue.Type()))
+	}
+	return info
+}
+
+func (builder *queryBuilder) getListLength(fieldValue reflect.Value) int {
+	if fieldValue.Kind() == reflect.Ptr {
+		fieldValue = fieldValue.Elem()
+	}
+	if fieldValue.Kind() != reflect.Slice && fieldValue.Kind() != reflect.Array {
+		panic(fmt.Sprintf("%s operator field should be slice or array", fieldValue.Type()))
+	}
+	return fieldValue.Len()
+}
+
+func isLikePatternTag(operatorTag string) bool {
+	return operatorTag == "prefix" || operatorTag == "suffix" || operatorTag == "contains"
+}
+
+// for prefix, suffix and contains operator tag. %, _, [ and \ in value are escaped by \.
+// [ is wildcard of SQL Server. condition of the tags renders ESCAPE '\'.
+// ex. LikePattern("prefix", "50%") => 50\%%
+func LikePattern(operatorTag, value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "[", `\[`).Replace(value)
+	switch operatorTag {
+	case "prefix":
+		return escaped + "%"
+	case "suffix":
+		return "%" + escaped
+	case "contains":
+		return "%" + escaped + "%"
+	default:
+		return value
+	}
+}
+
+// values of WhereInValues, subqueries and namedArgs. namedArgs takes priority
+func (builder *queryBuilder) getNamedArgs(namedArgs []map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(builder.values))
+	for _, sub := range builder.subQueries {
+		for name, value := range sub.getNamedArgs(nil) {
+			merged[name] = value
+		}
+	}
+	for name, value := range builder.values {
+		merged[name] = value
+	}
+	for _, m := range namedArgs {
+		for name, value := range m {
+			merged[name] = value
+		}
 	}
-	return sortedByFieldNumber
+	return merged
 }
 
 func (builder *queryBuilder) getReflectTypeAndValue(src interface{}) (reflect.Type, reflect.Value) {
@@ -0,0 +1,170 @@
+package query_builder
+
+import (
+	"strings"
+	"sync"
+)
+
+// conditions of table which are reused by builders. table is qualified name of main table or joined table.
+// ex. func TenantScope(table string) *Condition { return Cond(table+".tenant_id", Equal, "tenant_id") }
+type Scope func(table string) *Condition
+
+type namedScope struct {
+	name  string
+	scope Scope
+}
+
+// table => mandatory scopes in registered order
+var (
+	mandatoryScopes      = make(map[string][]namedScope)
+	mandatoryScopesMutex sync.RWMutex
+)
+
+// Unscoped without names removes all mandatory scopes
+const allScopes = "*"
+
+// scope is applied to all Select, Update and Delete builders of table, joins and subqueries included.
+// Unscoped(name) is the only way to remove it. same name replaces, nil scope unsets.
+// ex. SetMandatoryScope("users", "tenant", TenantScope)
+func SetMandatoryScope(table, name string, scope Scope) {
+	mandatoryScopesMutex.Lock()
+	defer mandatoryScopesMutex.Unlock()
+
+	table = normalizeTableName(table)
+	scopes := make([]namedScope, 0, len(mandatoryScopes[table])+1)
+	for _, registered := range mandatoryScopes[table] {
+		if registered.name != name {
+			scopes = append(scopes, registered)
+		}
+	}
+	if scope != nil {
+		scopes = append(scopes, namedScope{name: name, scope: scope})
+	}
+
+	if len(scopes) == 0 {
+		delete(mandatoryScopes, table)
+		return
+	}
+	mandatoryScopes[table] = scopes
+}
+
+func getMandatoryScopes(table string) []namedScope {
+	mandatoryScopesMutex.RLock()
+	defer mandatoryScopesMutex.RUnlock()
+	return mandatoryScopes[normalizeTableName(table)]
+}
+
+// key of table registry. schema and quotes are removed, and case is ignored.
+// ex. public."Users", [dbo].[users], USERS => users
+func normalizeTableName(table string) string {
+	start := 0
+	var close byte
+	for index := 0; index < len(table); index++ {
+		c := table[index]
+		switch {
+		case close == 0 && index == start && (c == '"' || c == '`'):
+			close = c
+		case close == 0 && index == start && c == '[':
+			close = ']'
+		case close != 0 && c == close && index+1 < len(table) && table[index+1] == close:
+			// doubled close is escaped close
+			index++
+		case close != 0 && c == close:
+			close = 0
+		case close == 0 && c == '.':
+			// schema is not a part of key
+			start = index + 1
+		}
+	}
+
+	name := strings.TrimSpace(table[start:])
+	if len(name) >= 2 {
+		if open, last := name[0], name[len(name)-1]; (open == '"' || open == '`') && last == open || open == '[' && last == ']' {
+			name = strings.Replace(name[1:len(name)-1], string(last)+string(last), string(last), -1)
+		}
+	}
+	return strings.ToLower(name)
+}
+
+func (builder *queryBuilder) scope(scopes ...Scope) *queryBuilder {
+	copied := builder.copy()
+	copied.scopes = append(append(make([]Scope, 0, len(builder.scopes)+len(scopes)), builder.scopes...), scopes...)
+	return copied
+}
+
+func (builder *queryBuilder) unscope(names ...string) *queryBuilder {
+	copied := builder.copy()
+	copied.unscoped = make(map[string]bool, len(builder.unscoped)+len(names))
+	for name := range builder.unscoped {
+		copied.unscoped[name] = true
+	}
+	if len(names) == 0 {
+		names = []string{allScopes}
+	}
+	for _, name := range names {
+		copied.unscoped[name] = true
+	}
+	return copied
+}
+
+// mandatory scopes of table except unscoped
+func (builder *queryBuilder) getMandatoryScopes(table string) []Scope {
+	if builder.unscoped[allScopes] {
+		return nil
+	}
+
+	scopes := make([]Scope, 0)
+	for _, registered := range getMandatoryScopes(table) {
+		if !builder.unscoped[registered.name] {
+			scopes = append(scopes, registered.scope)
+		}
+	}
+	return scopes
+}
+
+// mandatory scopes and scopes of builder are added to WHERE
+func (builder *queryBuilder) scoped() *queryBuilder {
+	scopes := append(builder.getMandatoryScopes(builder.tableName), builder.scopes...)
+	scoped := builder
+	for _, scope := range scopes {
+		scoped = scoped.guard(scope(builder.tableName))
+	}
+	return scoped
+}
+
+// condition is appended to restrict all rows. existing conditions are parenthesized when OR is used.
+// ex. WHERE (a = ? OR b = ?) AND deleted_at IS NULL
+func (builder *queryBuilder) guard(condition *Condition) *queryBuilder {
+	if condition == nil || len(condition.conditions) == 0 {
+		return builder
+	}
+
+	copied := builder.copy()
+	conditions := make([]map[string]string, 0, len(builder.whereConditions)+len(condition.conditions))
+	conditions = append(conditions, builder.whereConditions...)
+
+	// OR in parentheses doesn't matter
+	hasOr := false
+	depth := 0
+	for index, c := range conditions {
+		if index > 0 && depth == 0 && c["logical"] == "OR" {
+			hasOr = true
+		}
+		depth += strings.Count(c["open"], "(") - strings.Count(c["close"], ")")
+	}
+
+	if hasOr {
+		first := copyStringMap(conditions[0])
+		first["open"] = "(" + first["open"]
+		conditions[0] = first
+
+		last := copyStringMap(conditions[len(conditions)-1])
+		last["close"] += ")"
+		conditions[len(conditions)-1] = last
+	}
+
+	guards := wrapBindInfos(condition.copyConditions())
+	guards[0]["logical"] = "AND"
+	copied.whereConditions = append(conditions, guards...)
+	return copied
+}
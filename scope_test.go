@@ -0,0 +1,286 @@
+package query_builder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func tenantScope(table string) *Condition {
+	return Cond(table+".tenant_id", Equal, "tenant_id")
+}
+
+func activeScope(table string) *Condition {
+	return Cond(table+".status", Equal, "status").Or(table+".status", IsNull)
+}
+
+func Test_SelectQueryBuilder_Scope(t *testing.T) {
+	SetMandatoryScope("users", "tenant", tenantScope)
+	SetMandatoryScope("tasks", "tenant", tenantScope)
+	defer SetMandatoryScope("users", "tenant", nil)
+	defer SetMandatoryScope("tasks", "tenant", nil)
+
+	joinFields := []string{"user_id"}
+	builder := NewSelectQueryBuilder().
+		Table("users").
+		Join(InnerJoin, "tasks", joinFields, joinFields).
+		Where("name", Equal).
+		Or("email", Equal)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users INNER JOIN tasks ON users.user_id = tasks.user_id AND tasks.tenant_id = ? WHERE (name = ? OR email = ?) AND users.tenant_id = ?;",
+		builder.Build(),
+		true,
+	)
+
+	args, err := builder.Args(map[string]interface{}{"tenant_id": 7, "name": "trewanek", "email": "e"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{7, "trewanek", "e", 7}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+
+	// ON of RIGHT JOIN doesn't restrict joined table, so scope is in WHERE
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users RIGHT JOIN tasks ON users.user_id = tasks.user_id WHERE (name = ? OR email = ?) AND tasks.tenant_id = ? AND users.tenant_id = ?;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Join(RightJoin, "tasks", joinFields, joinFields).
+			Where("name", Equal).
+			Or("email", Equal).
+			Build(),
+		true,
+	)
+
+	// tenant_id is required
+	if _, err := builder.Args(map[string]interface{}{"name": "trewanek", "email": "e"}); err == nil {
+		t.Fail()
+	}
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users INNER JOIN tasks ON users.user_id = tasks.user_id WHERE name = ? OR email = ?;",
+		builder.Unscoped("tenant").Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE users.tenant_id = ? AND (users.status = ? OR users.status IS NULL);",
+		NewSelectQueryBuilder().Table("users").Scope(activeScope).Build(),
+		true,
+	)
+
+	subQuery := NewSelectQueryBuilder().Table("tasks").Column("user_id").Where("status", Equal)
+	subQueryBuilder := NewSelectQueryBuilder().
+		Table("users").
+		Where("name", Equal).
+		WhereSubQuery("users.user_id", In, subQuery)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE name = ? AND users.user_id IN (SELECT tasks.user_id FROM tasks WHERE status = ? AND tasks.tenant_id = ?) AND users.tenant_id = ?;",
+		subQueryBuilder.Build(),
+		true,
+	)
+
+	// subquery is numbered through whole query
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE name = $1 AND users.user_id IN (SELECT tasks.user_id FROM tasks WHERE status = $2 AND tasks.tenant_id = $3) AND users.tenant_id = $4;",
+		subQueryBuilder.Placeholder(DollarNumber).Build(),
+		false,
+	)
+
+	args, err = subQueryBuilder.Args(map[string]interface{}{"tenant_id": 7, "name": "trewanek", "status": "open"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs = []interface{}{"trewanek", "open", 7, 7}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+
+	// scope registered after WhereSubQuery is applied, and Unscoped of outer query reaches subquery
+	SetMandatoryScope("tasks", "active", activeScope)
+	defer SetMandatoryScope("tasks", "active", nil)
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE name = ? AND users.user_id IN (SELECT tasks.user_id FROM tasks WHERE status = ? AND tasks.tenant_id = ? AND (tasks.status = ? OR tasks.status IS NULL)) AND users.tenant_id = ?;",
+		subQueryBuilder.Build(),
+		true,
+	)
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE name = ? AND users.user_id IN (SELECT tasks.user_id FROM tasks WHERE status = ? AND (tasks.status = ? OR tasks.status IS NULL));",
+		subQueryBuilder.Unscoped("tenant").Build(),
+		true,
+	)
+}
+
+func Test_UpdateQueryBuilder_Scope(t *testing.T) {
+	SetMandatoryScope("users", "tenant", tenantScope)
+	defer SetMandatoryScope("users", "tenant", nil)
+
+	builder := NewUpdateQueryBuilder().
+		Table("users").
+		Column("name").
+		Where("user_id", Equal)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ? WHERE user_id = ? AND users.tenant_id = ?;",
+		builder.Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ? WHERE user_id = ?;",
+		builder.Unscoped().Build(),
+		true,
+	)
+}
+
+func Test_DeleteQueryBuilder_Scope(t *testing.T) {
+	SetMandatoryScope("users", "tenant", tenantScope)
+	defer SetMandatoryScope("users", "tenant", nil)
+
+	builder := NewDeleteQueryBuilder().
+		Table("users").
+		Where("user_id", Equal)
+
+	testCommonFunc(
+		t,
+		"DELETE FROM users WHERE user_id = ? AND users.tenant_id = ?;",
+		builder.Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE user_id = ? AND deleted_at IS NULL AND users.tenant_id = ?;",
+		builder.SoftDelete("deleted_at").Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"DELETE FROM users WHERE user_id = ?;",
+		builder.Unscoped("tenant").Build(),
+		true,
+	)
+}
+
+func Test_SelectQueryBuilder_ScopeTableName(t *testing.T) {
+	SetMandatoryScope("users", "tenant", tenantScope)
+	SetMandatoryScope(`"Tasks"`, "tenant", tenantScope)
+	defer SetMandatoryScope("users", "tenant", nil)
+	defer SetMandatoryScope("tasks", "tenant", nil)
+
+	tests := []struct {
+		name     string
+		builder  *SelectQueryBuilder
+		expected string
+	}{
+		{
+			name:     "schema",
+			builder:  NewSelectQueryBuilder().Table("public.users"),
+			expected: "SELECT public.users.* FROM public.users WHERE public.users.tenant_id = ?;",
+		},
+		{
+			name:     "upper case",
+			builder:  NewSelectQueryBuilder().Table("USERS"),
+			expected: "SELECT USERS.* FROM USERS WHERE USERS.tenant_id = ?;",
+		},
+		{
+			name:     "quoted",
+			builder:  NewSelectQueryBuilder().Table("`users`"),
+			expected: "SELECT `users`.* FROM `users` WHERE `users`.tenant_id = ?;",
+		},
+		{
+			name:     "bracketed schema",
+			builder:  NewSelectQueryBuilder().Table("[dbo].[Users]"),
+			expected: "SELECT [dbo].[Users].* FROM [dbo].[Users] WHERE [dbo].[Users].tenant_id = ?;",
+		},
+		{
+			name: "joined table",
+			builder: NewSelectQueryBuilder().
+				Table("public.users").
+				Join(InnerJoin, "public.tasks", []string{"user_id"}, []string{"user_id"}),
+			expected: "SELECT public.users.* FROM public.users INNER JOIN public.tasks ON public.users.user_id = public.tasks.user_id AND public.tasks.tenant_id = ? WHERE public.users.tenant_id = ?;",
+		},
+		{
+			name:     "other table",
+			builder:  NewSelectQueryBuilder().Table("users_log"),
+			expected: "SELECT users_log.* FROM users_log;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCommonFunc(t, tt.expected, tt.builder.Build(), false)
+		})
+	}
+}
+
+func Test_normalizeTableName(t *testing.T) {
+	tests := []struct {
+		table    string
+		expected string
+	}{
+		{"users", "users"},
+		{"Users", "users"},
+		{"public.users", "users"},
+		{`"public"."Users"`, "users"},
+		{"`db`.`users`", "users"},
+		{"[dbo].[users]", "users"},
+		{`"my.schema".users`, "users"},
+		{`"we""ird"`, `we"ird`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			if actual := normalizeTableName(tt.table); actual != tt.expected {
+				t.Logf("expected: %s, actual: %s", tt.expected, actual)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func Test_SelectQueryBuilder_ScopeStrict(t *testing.T) {
+	SetMandatoryScope("tasks", "unsafe", func(table string) *Condition {
+		return Cond(table+".tenant_id = 1; --", Equal, "tenant_id")
+	})
+	defer SetMandatoryScope("tasks", "unsafe", nil)
+
+	for _, joinType := range []string{InnerJoin, RightJoin} {
+		t.Run(joinType, func(t *testing.T) {
+			builder := NewSelectQueryBuilder().
+				Table("users").
+				Join(joinType, "tasks", []string{"user_id"}, []string{"user_id"})
+
+			// scope of joined table is validated
+			if err := builder.Validate(); !errors.Is(err, InvalidIdentifierErr) {
+				t.Logf("expected: %v, actual: %v", InvalidIdentifierErr, err)
+				t.Fail()
+			}
+
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, InvalidIdentifierErr) {
+					t.Logf("expected: %v, actual: %v", InvalidIdentifierErr, err)
+					t.Fail()
+				}
+			}()
+			_ = builder.Strict().Build()
+		})
+	}
+}
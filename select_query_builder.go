@@ -2,17 +2,18 @@ package query_builder
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 )
 
 type SelectQueryBuilder struct {
 	joins         []map[string]interface{}
 	groupByColumn string
-	order         map[string]string
+	groupByRaw    bool
+	windows       []map[string]interface{}
+	orders        []map[string]interface{}
 	limit         map[string]interface{}
 	offset        map[string]interface{}
+	lock          map[string]interface{}
 	*queryBuilder
 	subQueryBuilder *queryBuilder
 }
@@ -28,9 +29,12 @@ func (builder *SelectQueryBuilder) copy() *SelectQueryBuilder {
 	return &SelectQueryBuilder{
 		builder.joins,
 		builder.groupByColumn,
-		builder.order,
+		builder.groupByRaw,
+		builder.windows,
+		builder.orders,
 		builder.limit,
 		builder.offset,
+		builder.lock,
 		builder.queryBuilder.copy(),
 		nil,
 	}
@@ -43,15 +47,116 @@ func (builder *SelectQueryBuilder) Placeholder(placeholderType int) *SelectQuery
 	return copied
 }
 
+// Default dialect is MySQL. dialect decides how locking clause is rendered and quote characters.
+func (builder *SelectQueryBuilder) Dialect(dialect int) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setDialect(dialect)
+	return copied
+}
+
+// Default is QuoteNever. QuoteWhenNeeded quotes reserved words and identifiers including special characters.
+func (builder *SelectQueryBuilder) Quote(quoteMode int) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.quote(quoteMode)
+	return copied
+}
+
+// Strict mode panics on Build when identifiers are not safe. expressions need to be wrapped by Raw.
+func (builder *SelectQueryBuilder) Strict() *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setStrict()
+	return copied
+}
+
+// WhereInValues renders = ANY(bind) and WhereNotInValues renders <> ALL(bind) with slice value. PostgreSQL only
+func (builder *SelectQueryBuilder) ArrayBinding() *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setArrayBinding()
+	return copied
+}
+
+// soft delete column of this builder. overrides SetSoftDelete. ex. SoftDelete("deleted_at")
+// joined tables use SetSoftDelete or JoinSoftDelete.
+func (builder *SelectQueryBuilder) SoftDelete(column string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.softDelete(column)
+	return copied
+}
+
+// soft delete column of joined table. overrides SetSoftDelete, empty column disables. call after Join
+// ex. JoinSoftDelete("prefectures", "")
+func (builder *SelectQueryBuilder) JoinSoftDelete(table, column string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.joins = make([]map[string]interface{}, 0, len(builder.joins))
+	for _, join := range builder.joins {
+		if join["table"] == table {
+			m := make(map[string]interface{}, len(join)+1)
+			for key, value := range join {
+				m[key] = value
+			}
+			m["softDelete"] = column
+			join = m
+		}
+		copied.joins = append(copied.joins, join)
+	}
+	return copied
+}
+
+// soft deleted rows are not excluded
+func (builder *SelectQueryBuilder) WithTrashed() *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setTrashed(withTrashed)
+	return copied
+}
+
+// only soft deleted rows. ex. WHERE deleted_at IS NOT NULL
+func (builder *SelectQueryBuilder) OnlyTrashed() *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setTrashed(onlyTrashed)
+	return copied
+}
+
+// scopes are added to WHERE with table on Build. ex. Scope(ActiveScope)
+func (builder *SelectQueryBuilder) Scope(scopes ...Scope) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.scope(scopes...)
+	return copied
+}
+
+// removes mandatory scopes of SetMandatoryScope by name, all scopes without names. ex. Unscoped("tenant")
+func (builder *SelectQueryBuilder) Unscoped(names ...string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.unscope(names...)
+	return copied
+}
+
 func (builder *SelectQueryBuilder) Table(tableName string) *SelectQueryBuilder {
 	copied := builder.copy()
 	copied.queryBuilder = builder.table(tableName)
 	return copied
 }
 
+// fields of joined tables are also selected as table.column. call after Join, Join of skipped table panics
 func (builder *SelectQueryBuilder) Model(src interface{}) *SelectQueryBuilder {
 	copied := builder.copy()
-	copied.queryBuilder = builder.model(src, true)
+	copied.queryBuilder = builder.model(src, modelSelect, builder.getJoinTables(), true)
+	return copied
+}
+
+// columns of joined tables are aliased as {table}__{column} to scan into nested struct.
+// column and alias are quoted by Quote mode. ex. orders.total AS orders__total
+func (builder *SelectQueryBuilder) ModelWithAlias(src interface{}) *SelectQueryBuilder {
+	copied := builder.Model(src)
+	columnAliases := make(map[int]string, len(copied.columnAliases)+len(copied.columns)-len(builder.columns))
+	for index, alias := range copied.columnAliases {
+		columnAliases[index] = alias
+	}
+	for index := len(builder.columns); index < len(copied.columns); index++ {
+		if split := strings.SplitN(copied.columns[index], ".", 2); len(split) == 2 {
+			columnAliases[index] = split[0] + "__" + split[1]
+		}
+	}
+	copied.columnAliases = columnAliases
 	return copied
 }
 
@@ -61,6 +166,38 @@ func (builder *SelectQueryBuilder) Column(columns ...string) *SelectQueryBuilder
 	return copied
 }
 
+// expression is appended to columns as it is. ex. Column("user_id").ColumnRaw(Raw("COUNT(*) AS cnt"))
+func (builder *SelectQueryBuilder) ColumnRaw(expressions ...RawExpr) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.rawColumn(expressions...)
+	return copied
+}
+
+// ex. WindowColumn(Over("ROW_NUMBER()").PartitionBy("user_id").OrderBy("created", Desc), "row_num")
+func (builder *SelectQueryBuilder) WindowColumn(window *Window, alias string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.windowColumn(window, alias)
+	return copied
+}
+
+// ex. Window("w", NewWindow().PartitionBy("user_id")) => WINDOW w AS (PARTITION BY user_id)
+func (builder *SelectQueryBuilder) Window(name string, definition *Window) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.windows = append(append(make([]map[string]interface{}, 0, len(builder.windows)+1), builder.windows...), map[string]interface{}{
+		"name":       name,
+		"definition": definition,
+	})
+	return copied
+}
+
+// ex. CaseColumn(Case().When(Cond("status", Equal), "'active'").Else("'inactive'"), "status_label")
+// => CASE WHEN status = ? THEN 'active' ELSE 'inactive' END AS status_label
+func (builder *SelectQueryBuilder) CaseColumn(expression *CaseExpression, alias string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.caseColumn(alias, expression)
+	return copied
+}
+
 func (builder *SelectQueryBuilder) Join(joinType, joinTable string, onOriginFields, onTargetFields []string, otherTable ...string) *SelectQueryBuilder {
 	copied := builder.copy()
 
@@ -68,6 +205,10 @@ func (builder *SelectQueryBuilder) Join(joinType, joinTable string, onOriginFiel
 		panic("origin fields and target fields need to be same length")
 	}
 
+	if containsString(builder.modelSkipped, joinTable) {
+		panic(ModelJoinOrderErr)
+	}
+
 	m := make(map[string]interface{})
 	m["type"] = joinType
 	m["table"] = joinTable
@@ -94,6 +235,32 @@ func (builder *SelectQueryBuilder) Or(column, operator string, bind ...string) *
 	return copied
 }
 
+// ex. WhereRaw(Raw("LOWER(name)"), Equal, "name") => WHERE LOWER(name) = ?
+func (builder *SelectQueryBuilder) WhereRaw(expression RawExpr, operator string, bind ...string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereRaw("AND", expression, operator, bind...)
+	return copied
+}
+
+func (builder *SelectQueryBuilder) OrRaw(expression RawExpr, operator string, bind ...string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereRaw("OR", expression, operator, bind...)
+	return copied
+}
+
+// ex. WhereCondition(AnyOf(Cond("name", Like), Cond("email", Like))) => WHERE (name LIKE ? OR email LIKE ?)
+func (builder *SelectQueryBuilder) WhereCondition(condition *Condition) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereCondition("AND", condition)
+	return copied
+}
+
+func (builder *SelectQueryBuilder) OrCondition(condition *Condition) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereCondition("OR", condition)
+	return copied
+}
+
 func (builder *SelectQueryBuilder) WhereIn(column string, listLength int, bind ...string) *SelectQueryBuilder {
 	copied := builder.copy()
 	copied.queryBuilder = builder.whereIn(column, listLength, bind...)
@@ -106,6 +273,18 @@ func (builder *SelectQueryBuilder) WhereNotIn(column string, listLength int, bin
 	return copied
 }
 
+func (builder *SelectQueryBuilder) WhereInValues(column string, values interface{}, bind ...string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereInValues(In, column, values, bind...)
+	return copied
+}
+
+func (builder *SelectQueryBuilder) WhereNotInValues(column string, values interface{}, bind ...string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereInValues(NotIn, column, values, bind...)
+	return copied
+}
+
 func (builder *SelectQueryBuilder) WhereSubQuery(column, operator string, subQueryBuilder *SelectQueryBuilder) *SelectQueryBuilder {
 	copied := builder.copy()
 	copied.queryBuilder = builder.whereSubQuery(column, operator, subQueryBuilder)
@@ -131,17 +310,83 @@ func (builder *SelectQueryBuilder) WhereMultiByStruct(src interface{}) *SelectQu
 
 func (builder *SelectQueryBuilder) GroupBy(column string) *SelectQueryBuilder {
 	copied := builder.copy()
-	copied.groupByColumn = column
+	copied.groupByColumn, copied.groupByRaw = column, false
+	return copied
+}
+
+// ex. GroupByRaw(Raw("DATE(created)"))
+func (builder *SelectQueryBuilder) GroupByRaw(expression RawExpr) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.groupByColumn, copied.groupByRaw = expression.expression, true
 	return copied
 }
 
 // ex. OrderBy("created, user_id", Asc)
 func (builder *SelectQueryBuilder) OrderBy(columns, order string) *SelectQueryBuilder {
 	copied := builder.copy()
-	copied.order = map[string]string{
+	copied.orders = []map[string]interface{}{{
 		"columns": columns,
 		"order":   order,
+	}}
+	return copied
+}
+
+// ex. OrderByRaw(Raw("COUNT(*)"), Desc)
+func (builder *SelectQueryBuilder) OrderByRaw(expression RawExpr, order string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.orders = []map[string]interface{}{{
+		"columns": expression.expression,
+		"raw":     true,
+		"order":   order,
+	}}
+	return copied
+}
+
+// input is api sort parameter. allowed maps api field name to db column.
+// ex. OrderByAllowed("-created,name", map[string]string{"created": "users.created_at", "name": "users.name"})
+// => ORDER BY users.created_at DESC, users.name ASC
+// returns *UnknownSortFieldError when field is not allowed.
+func (builder *SelectQueryBuilder) OrderByAllowed(input string, allowed map[string]string) (*SelectQueryBuilder, error) {
+	specs, err := ParseSortSpec(input)
+	if err != nil {
+		return nil, err
 	}
+
+	copied := builder.copy()
+	if len(specs) == 0 {
+		return copied, nil
+	}
+
+	copied.orders = make([]map[string]interface{}, 0, len(specs))
+	for _, spec := range specs {
+		column, ok := allowed[spec.Field]
+		if !ok {
+			return nil, &UnknownSortFieldError{Field: spec.Field}
+		}
+		copied.orders = append(copied.orders, map[string]interface{}{
+			"columns": column,
+			"order":   spec.Order,
+		})
+	}
+	return copied, nil
+}
+
+func (builder *SelectQueryBuilder) OrderByCase(expression *CaseExpression, order string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.orders = []map[string]interface{}{{
+		"case":  expression,
+		"order": order,
+	}}
+	return copied
+}
+
+// ex. OrderByWindow(Over("ROW_NUMBER()").PartitionBy("user_id"), Asc) => ORDER BY ROW_NUMBER() OVER (PARTITION BY user_id) ASC
+func (builder *SelectQueryBuilder) OrderByWindow(window *Window, order string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.orders = []map[string]interface{}{{
+		"window": window,
+		"order":  order,
+	}}
 	return copied
 }
 
@@ -171,15 +416,196 @@ func (builder *SelectQueryBuilder) Offset(bind ...string) *SelectQueryBuilder {
 	return copied
 }
 
+func (builder *SelectQueryBuilder) ForUpdate() *SelectQueryBuilder {
+	return builder.lockStrength(LockForUpdate)
+}
+
+// PostgreSQL only
+func (builder *SelectQueryBuilder) ForNoKeyUpdate() *SelectQueryBuilder {
+	return builder.lockStrength(LockForNoKeyUpdate)
+}
+
+func (builder *SelectQueryBuilder) ForShare() *SelectQueryBuilder {
+	return builder.lockStrength(LockForShare)
+}
+
+// ex. ForUpdate().Of("users") => FOR UPDATE OF users
+func (builder *SelectQueryBuilder) Of(tables ...string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.lock = builder.copyLock()
+	of, _ := copied.lock["of"].([]string)
+	copied.lock["of"] = append(append(make([]string, 0, len(of)+len(tables)), of...), tables...)
+	return copied
+}
+
+func (builder *SelectQueryBuilder) NoWait() *SelectQueryBuilder {
+	return builder.lockWait(LockNoWait)
+}
+
+func (builder *SelectQueryBuilder) SkipLocked() *SelectQueryBuilder {
+	return builder.lockWait(LockSkipLocked)
+}
+
+func (builder *SelectQueryBuilder) lockStrength(strength string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.lock = builder.copyLock()
+	copied.lock["strength"] = strength
+	return copied
+}
+
+func (builder *SelectQueryBuilder) lockWait(wait string) *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.lock = builder.copyLock()
+	if copied.lock["wait"] != nil && copied.lock["wait"] != wait {
+		panic(LockWaitPolicyConflictErr)
+	}
+	copied.lock["wait"] = wait
+	return copied
+}
+
+func (builder *SelectQueryBuilder) copyLock() map[string]interface{} {
+	lock := make(map[string]interface{}, len(builder.lock))
+	for key, value := range builder.lock {
+		lock[key] = value
+	}
+	return lock
+}
+
+// returns error when identifiers are not safe. Strict mode uses this on Build.
+func (builder *SelectQueryBuilder) Validate() error {
+	// conditions of mandatory scopes are validated with joins
+	scoped := builder.scopedJoins()
+	if err := scoped.validate(); err != nil {
+		return err
+	}
+	for _, join := range scoped.joins {
+		if !joinTypes[join["type"].(string)] {
+			return fmt.Errorf("%w: join type %q", InvalidIdentifierErr, join["type"])
+		}
+		identifiers := append([]string{join["table"].(string)}, join["onOriginFields"].([]string)...)
+		identifiers = append(identifiers, join["onTargetFields"].([]string)...)
+		if join["otherTable"] != nil {
+			identifiers = append(identifiers, join["otherTable"].(string))
+		}
+		for _, identifier := range identifiers {
+			if err := builder.validateIdentifier(identifier, false); err != nil {
+				return err
+			}
+		}
+		if conditions, ok := join["conditions"].([]map[string]string); ok {
+			if err := builder.validateConditions(conditions); err != nil {
+				return err
+			}
+		}
+	}
+
+	if builder.groupByColumn != "" {
+		if err := builder.validateIdentifiers(builder.groupByColumn, builder.groupByRaw); err != nil {
+			return err
+		}
+	}
+
+	for _, window := range builder.windows {
+		if err := builder.validateIdentifier(window["name"].(string), false); err != nil {
+			return err
+		}
+		if err := builder.validateWindow(window["definition"].(*Window)); err != nil {
+			return err
+		}
+	}
+
+	for _, order := range builder.orders {
+		if order["order"] != Asc && order["order"] != Desc {
+			return fmt.Errorf("%w: order %q", InvalidIdentifierErr, order["order"])
+		}
+
+		var err error
+		if expression, ok := order["case"].(*CaseExpression); ok {
+			err = builder.validateCase(expression)
+		} else if window, ok := order["window"].(*Window); ok {
+			err = builder.validateWindow(window)
+		} else {
+			raw, _ := order["raw"].(bool)
+			err = builder.validateIdentifiers(order["columns"].(string), raw)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, paging := range []map[string]interface{}{builder.limit, builder.offset} {
+		if bind, ok := paging["bind"].(string); ok {
+			if err := builder.validateBind(bind); err != nil {
+				return err
+			}
+		}
+	}
+
+	if of, _ := builder.lock["of"].([]string); len(of) > 0 {
+		return builder.validateIdentifiers(strings.Join(of, ", "), false)
+	}
+	return nil
+}
+
+// args in placeholder order for Question and DollarNumber. values of WhereInValues are merged with namedArgs.
+// query is built as Named and compiled, so binds need to be unique.
+func (builder *SelectQueryBuilder) Args(namedArgs ...map[string]interface{}) ([]interface{}, error) {
+	_, args, err := Compile(builder.Placeholder(Named).Build(), builder.getNamedArgs(namedArgs), Question)
+	return args, err
+}
+
+// query of placeholder type and args in placeholder order, from the build that renders values of WhereMultiByStruct and WhereInValues.
+// namedArgs are merged for other binds. Named keeps query as it is, use NamedArgs for named binding.
+// ex. query, args, err := qb.WhereMultiByStruct(searchParam).BuildWithArgs(); db.Query(query, args...)
+func (builder *SelectQueryBuilder) BuildWithArgs(namedArgs ...map[string]interface{}) (string, []interface{}, error) {
+	return Compile(builder.Placeholder(Named).Build(), builder.getNamedArgs(namedArgs), builder.placeholderType)
+}
+
+// args by bind of Named query. list binds are expanded. ex. {"user_ids1": 1, "user_ids2": 2}
+func (builder *SelectQueryBuilder) NamedArgs(namedArgs ...map[string]interface{}) (map[string]interface{}, error) {
+	_, args, names, err := compile(builder.Placeholder(Named).Build(), builder.getNamedArgs(namedArgs), Named)
+	if err != nil {
+		return nil, err
+	}
+	named := make(map[string]interface{}, len(names))
+	for index, name := range names {
+		named[name] = args[index]
+	}
+	return named, nil
+}
+
 func (builder *SelectQueryBuilder) Build() string {
+	query, _ := builder.build(0)
+	return query + ";"
+}
+
+// placeholders are numbered from argNum on the copy, so Build can be called repeatedly.
+// returns query without ; and last number. ex. INSERT ... SELECT continues numbering
+func (builder *SelectQueryBuilder) build(argNum int) (string, int) {
 	if builder.tableName == "" {
 		panic("target table is empty!!!")
 	}
 
+	// soft delete condition and scopes are added on Build, so Where can be called after WithTrashed or Unscoped
+	scoped := builder.scopedJoins()
+	scoped.queryBuilder = scoped.softDeleteScope(true).scoped()
+	scoped.argNum = argNum
+	builder = scoped
+
+	if builder.strict {
+		if err := builder.Validate(); err != nil {
+			panic(err)
+		}
+	}
+
 	copied := builder.copy()
 	columns := builder.columns
 	copied.query = append(copied.query, builder.getSelectParagraphs(builder.tableName, columns)...)
 
+	if len(builder.lock) > 0 && builder.dialect == SQLServer {
+		copied.query = append(copied.query, builder.getTableHintParagraph())
+	}
+
 	if len(builder.joins) > 0 {
 		copied.query = append(copied.query, builder.getJoinParagraphs(builder.tableName)...)
 	}
@@ -192,19 +618,27 @@ func (builder *SelectQueryBuilder) Build() string {
 		copied.query = append(copied.query, builder.getGroupByParagraph())
 	}
 
-	if len(builder.order) > 0 {
+	if len(builder.windows) > 0 {
+		copied.query = append(copied.query, builder.getWindowParagraph())
+	}
+
+	if len(builder.orders) > 0 {
 		copied.query = append(copied.query, builder.getOrderParagraph())
 	}
 
 	if builder.limit["use"] != nil && builder.limit["use"].(bool) {
-		copied.query = append(copied.query, builder.getLimitParagraph(builder.placeholderType))
+		copied.query = append(copied.query, builder.getLimitParagraph())
 	}
 
 	if builder.offset["use"] != nil && builder.offset["use"].(bool) {
-		copied.query = append(copied.query, builder.getOffsetParagraph(builder.placeholderType))
+		copied.query = append(copied.query, builder.getOffsetParagraph())
 	}
 
-	return strings.TrimRight(strings.Join(copied.query, " "), "") + ";"
+	if len(builder.lock) > 0 && builder.dialect != SQLServer {
+		copied.query = append(copied.query, builder.getLockParagraph())
+	}
+
+	return strings.TrimRight(strings.Join(copied.query, " "), ""), builder.argNum
 }
 
 func (builder *SelectQueryBuilder) getSelectParagraphs(tableName string, columns []string) []string {
@@ -212,24 +646,28 @@ func (builder *SelectQueryBuilder) getSelectParagraphs(tableName string, columns
 	paragraphs = append(paragraphs, "SELECT")
 
 	if len(columns) == 0 {
-		paragraphs = append(paragraphs, tableName+".*")
-		paragraphs = append(paragraphs, "FROM", tableName)
+		paragraphs = append(paragraphs, builder.quoteIdentifier(tableName+".*"))
+		paragraphs = append(paragraphs, "FROM", builder.quoteIdentifier(tableName))
 		return paragraphs
 	}
 
-	format := "%s.%s,"
 	for index, column := range columns {
-		table, selectColumn := tableName, column
-		split := strings.Split(column, ".")
-		if len(split) > 1 {
-			table, selectColumn = split[0], split[1]
-		}
-
 		var paragraph string
-		if regexp.MustCompile(`^.*\(.*\)`).Match([]byte(column)) {
-			paragraph = fmt.Sprintf("%s,", selectColumn)
+		if expression := builder.cases[index]; expression != nil {
+			paragraph = fmt.Sprintf("%s AS %s,", expression.build(builder.queryBuilder), builder.quoteIdentifier(column))
+		} else if window := builder.windowColumns[index]; window != nil {
+			paragraph = window.build(builder.queryBuilder) + ","
+			if column != "" {
+				paragraph = fmt.Sprintf("%s AS %s,", window.build(builder.queryBuilder), builder.quoteIdentifier(column))
+			}
+		} else if builder.rawColumns[index] || functionRegexp.MatchString(column) {
+			// function call is not quoted in any quote mode. Strict mode rejects it without Raw
+			paragraph = fmt.Sprintf("%s,", column)
+		} else if strings.Contains(column, ".") {
+			// already qualified. ex. table.column, schema.table.column
+			paragraph = fmt.Sprintf("%s%s,", builder.quoteIdentifier(column), builder.getColumnAlias(index))
 		} else {
-			paragraph = fmt.Sprintf(format, table, selectColumn)
+			paragraph = fmt.Sprintf("%s%s,", builder.quoteIdentifier(tableName+"."+column), builder.getColumnAlias(index))
 		}
 
 		if index == len(columns)-1 {
@@ -238,7 +676,65 @@ func (builder *SelectQueryBuilder) getSelectParagraphs(tableName string, columns
 
 		paragraphs = append(paragraphs, paragraph)
 	}
-	return append(paragraphs, "FROM", tableName)
+	return append(paragraphs, "FROM", builder.quoteIdentifier(tableName))
+}
+
+// " AS {alias}" of ModelWithAlias, empty when column has no alias
+func (builder *SelectQueryBuilder) getColumnAlias(index int) string {
+	alias, ok := builder.columnAliases[index]
+	if !ok {
+		return ""
+	}
+	return " AS " + builder.quoteIdentifier(alias)
+}
+
+func (builder *SelectQueryBuilder) getJoinTables() []string {
+	tables := make([]string, 0, len(builder.joins))
+	for _, join := range builder.joins {
+		tables = append(tables, join["table"].(string))
+	}
+	return tables
+}
+
+// soft delete and mandatory scopes of joined tables are added to ON.
+// ON of RIGHT JOIN doesn't restrict rows of joined table, so they are added to WHERE instead.
+func (builder *SelectQueryBuilder) scopedJoins() *SelectQueryBuilder {
+	copied := builder.copy()
+	copied.joins = make([]map[string]interface{}, 0, len(builder.joins))
+	for _, join := range builder.joins {
+		table := join["table"].(string)
+		guards := make([]*Condition, 0)
+		if column := getJoinSoftDeleteColumn(join); column != "" && builder.trashed != withTrashed {
+			guards = append(guards, Cond(table+"."+column, IsNull))
+		}
+		for _, scope := range builder.getMandatoryScopes(table) {
+			guards = append(guards, scope(table))
+		}
+
+		conditions := make([]map[string]string, 0)
+		for _, condition := range guards {
+			if condition == nil || len(condition.conditions) == 0 {
+				continue
+			}
+			if join["type"] == RightJoin {
+				copied.queryBuilder = copied.guard(condition)
+				continue
+			}
+			scoped := wrapBindInfos(condition.copyConditions())
+			scoped[0]["logical"] = "AND"
+			conditions = append(conditions, scoped...)
+		}
+
+		m := make(map[string]interface{}, len(join)+1)
+		for key, value := range join {
+			m[key] = value
+		}
+		if len(conditions) > 0 {
+			m["conditions"] = conditions
+		}
+		copied.joins = append(copied.joins, m)
+	}
+	return copied
 }
 
 func (builder *SelectQueryBuilder) getJoinParagraphs(tableName string) []string {
@@ -249,13 +745,16 @@ func (builder *SelectQueryBuilder) getJoinParagraphs(tableName string) []string
 			joinOrginTableBase = join["otherTable"].(string)
 		}
 
-		paragraphFormer := fmt.Sprintf("%s %s ON ", join["type"], join["table"])
+		paragraphFormer := fmt.Sprintf("%s %s ON ", join["type"], builder.quoteIdentifier(join["table"].(string)))
 		paragraphLastHalf := builder.buildOnParagraph(
 			joinOrginTableBase,
 			join["table"].(string),
 			join["onOriginFields"].([]string),
 			join["onTargetFields"].([]string),
 		)
+		if conditions, ok := join["conditions"].([]map[string]string); ok {
+			paragraphLastHalf += " " + strings.Join(builder.getConditionParagraphs("AND", conditions), " ")
+		}
 		paragraph = append(paragraph, paragraphFormer+paragraphLastHalf)
 	}
 	return paragraph
@@ -270,48 +769,106 @@ func (builder *SelectQueryBuilder) buildOnParagraph(
 	onParagraph := make([]string, 0, 0)
 	for index, originField := range originFields {
 		onParagraph = append(onParagraph, fmt.Sprintf(
-			"%s.%s = %s.%s",
-			joinOriginTable,
-			originField,
-			joinTargetTable,
-			targetFields[index],
+			"%s = %s",
+			builder.quoteIdentifier(joinOriginTable+"."+originField),
+			builder.quoteIdentifier(joinTargetTable+"."+targetFields[index]),
 		))
 	}
 	return strings.Join(onParagraph, " AND ")
 }
 
 func (builder *SelectQueryBuilder) getGroupByParagraph() string {
-	return fmt.Sprintf("GROUP BY %s", builder.groupByColumn)
+	if builder.groupByRaw {
+		return fmt.Sprintf("GROUP BY %s", builder.groupByColumn)
+	}
+	return fmt.Sprintf("GROUP BY %s", builder.quoteIdentifiers(builder.groupByColumn))
 }
 
-func (builder *SelectQueryBuilder) getOrderParagraph() string {
-	return fmt.Sprintf("ORDER BY %s %s", builder.order["columns"], builder.order["order"])
+func (builder *SelectQueryBuilder) getWindowParagraph() string {
+	definitions := make([]string, 0, len(builder.windows))
+	for _, window := range builder.windows {
+		definitions = append(definitions, fmt.Sprintf(
+			"%s AS %s",
+			builder.quoteIdentifier(window["name"].(string)),
+			window["definition"].(*Window).build(builder.queryBuilder),
+		))
+	}
+	return "WINDOW " + strings.Join(definitions, ", ")
 }
 
-func (builder *SelectQueryBuilder) getLimitParagraph(placeholder int) string {
-	bind := "?"
-	if placeholder == Named {
-		bind = ":" + builder.limit["bind"].(string)
-	}
-	if placeholder == DollarNumber {
-		bind = "$" + strconv.Itoa(builder.argNum+1)
-		builder.argNum += 1
+func (builder *SelectQueryBuilder) getOrderParagraph() string {
+	paragraphs := make([]string, 0, len(builder.orders))
+	for _, order := range builder.orders {
+		var columns string
+		if expression, ok := order["case"].(*CaseExpression); ok {
+			columns = expression.build(builder.queryBuilder)
+		} else if window, ok := order["window"].(*Window); ok {
+			columns = window.build(builder.queryBuilder)
+		} else if raw, _ := order["raw"].(bool); raw {
+			columns = order["columns"].(string)
+		} else {
+			columns = builder.quoteIdentifiers(order["columns"].(string))
+		}
+		paragraphs = append(paragraphs, fmt.Sprintf("%s %s", columns, order["order"]))
 	}
-	return fmt.Sprintf("LIMIT %s", bind)
+	return fmt.Sprintf("ORDER BY %s", strings.Join(paragraphs, ", "))
+}
+
+func (builder *SelectQueryBuilder) getLimitParagraph() string {
+	return fmt.Sprintf("LIMIT %s", builder.getBind(builder.limit["bind"].(string)))
 }
 
-func (builder *SelectQueryBuilder) getOffsetParagraph(placeholder int) string {
+func (builder *SelectQueryBuilder) getOffsetParagraph() string {
 	if builder.limit == nil {
 		panic("offset is limit required")
 	}
+	return fmt.Sprintf("OFFSET %s", builder.getBind(builder.offset["bind"].(string)))
+}
+
+// MySQL 8: FOR UPDATE | FOR SHARE [OF ...] [NOWAIT | SKIP LOCKED]
+// PostgreSQL: FOR UPDATE | FOR NO KEY UPDATE | FOR SHARE [OF ...] [NOWAIT | SKIP LOCKED]
+func (builder *SelectQueryBuilder) getLockParagraph() string {
+	strength, _ := builder.lock["strength"].(string)
+	if strength == "" {
+		panic(LockStrengthRequiredErr)
+	}
+	if strength == LockForNoKeyUpdate && builder.dialect != PostgreSQL {
+		panic(UnsupportedLockErr)
+	}
 
-	bind := "?"
-	if placeholder == Named {
-		bind = ":" + builder.offset["bind"].(string)
+	paragraph := []string{strength}
+	if of, _ := builder.lock["of"].([]string); len(of) > 0 {
+		paragraph = append(paragraph, "OF", builder.quoteIdentifiers(strings.Join(of, ", ")))
 	}
-	if placeholder == DollarNumber {
-		bind = "$" + strconv.Itoa(builder.argNum+1)
-		builder.argNum += 1
+	if wait, _ := builder.lock["wait"].(string); wait != "" {
+		paragraph = append(paragraph, wait)
+	}
+	return strings.Join(paragraph, " ")
+}
+
+// SQLServer: WITH (UPDLOCK | HOLDLOCK [, NOWAIT | READPAST])
+func (builder *SelectQueryBuilder) getTableHintParagraph() string {
+	if of, _ := builder.lock["of"].([]string); len(of) > 0 {
+		panic(UnsupportedLockErr)
+	}
+
+	hints := make([]string, 0, 2)
+	switch builder.lock["strength"] {
+	case LockForUpdate:
+		hints = append(hints, "UPDLOCK")
+	case LockForShare:
+		hints = append(hints, "HOLDLOCK")
+	case nil:
+		panic(LockStrengthRequiredErr)
+	default:
+		panic(UnsupportedLockErr)
+	}
+
+	switch builder.lock["wait"] {
+	case LockNoWait:
+		hints = append(hints, "NOWAIT")
+	case LockSkipLocked:
+		hints = append(hints, "READPAST")
 	}
-	return fmt.Sprintf("OFFSET %s", bind)
+	return fmt.Sprintf("WITH (%s)", strings.Join(hints, ", "))
 }
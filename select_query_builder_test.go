@@ -1,6 +1,7 @@
 package query_builder
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -44,6 +45,47 @@ func Test_SelectQueryBuilder_Model(t *testing.T) {
 	)
 }
 
+func Test_SelectQueryBuilder_ModelEmbedded(t *testing.T) {
+	type BaseModel struct {
+		ID        int       `db:"id"`
+		CreatedAt time.Time `db:"created_at"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+	type Address struct {
+		City string `db:"city"`
+		Zip  string `db:"zip"`
+	}
+	type Customer struct {
+		BaseModel `table:"customers"`
+		Name      string    `db:"name" table:"customers"`
+		UpdatedAt time.Time `db:"updated_at" table:"customers"`
+		Address   Address   `table:"customers" prefix:"billing_"`
+		Shipping  *Address  `table:"customers" prefix:"shipping_"`
+		Created   time.Time `db:"created" table:"customers"`
+	}
+
+	testCommonFunc(
+		t,
+		"SELECT customers.id, customers.created_at, customers.name, customers.updated_at, "+
+			"customers.billing_city, customers.billing_zip, customers.created FROM customers;",
+		NewSelectQueryBuilder().
+			Table("customers").
+			Model(Customer{}).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"INSERT INTO customers(id, name, shipping_city) VALUES(?, ?, ?);",
+		NewInsertQueryBuilder().
+			Table("customers").
+			Model(Customer{BaseModel: BaseModel{ID: 1}, Name: "trewanek", Shipping: &Address{City: "Tokyo"}}).
+			Build(),
+		true,
+	)
+}
+
 func Test_SelectQueryBuilder_Column(t *testing.T) {
 	testCommonFunc(
 		t,
@@ -650,6 +692,125 @@ func Test_SelectQueryBuilder_Join(t *testing.T) {
 	}
 }
 
+func Test_SelectQueryBuilder_JoinModel(t *testing.T) {
+	type UserOrder struct {
+		UserID  string `db:"user_id" table:"users"`
+		Name    string `db:"name" table:"users"`
+		OrderID string `db:"order_id" table:"orders"`
+		Total   int    `db:"total" table:"orders"`
+		Memo    string `db:"memo" table:"memos"`
+	}
+	joinFields := []string{"user_id"}
+
+	testCommonFunc(
+		t,
+		"SELECT users.user_id, users.name, orders.order_id, orders.total FROM users LEFT JOIN orders ON users.user_id = orders.user_id;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Join(LeftJoin, "orders", joinFields, joinFields).
+			Model(UserOrder{}).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.user_id, users.name, orders.order_id AS orders__order_id, orders.total AS orders__total FROM users LEFT JOIN orders ON users.user_id = orders.user_id;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Join(LeftJoin, "orders", joinFields, joinFields).
+			ModelWithAlias(UserOrder{}).
+			Strict().
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.user_id, users.name FROM users;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Model(UserOrder{}).
+			Build(),
+		true,
+	)
+
+	// same column of different tables is not shadowed
+	type UserOrderID struct {
+		UserID  int `db:"id" table:"users"`
+		OrderID int `db:"id" table:"orders"`
+		Total   int `db:"total" table:"orders"`
+	}
+	testCommonFunc(
+		t,
+		"SELECT users.id, orders.id AS orders__id, orders.total AS orders__total FROM users LEFT JOIN orders ON users.user_id = orders.user_id;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Join(LeftJoin, "orders", joinFields, joinFields).
+			ModelWithAlias(UserOrderID{}).
+			Build(),
+		true,
+	)
+
+	// column and alias are quoted same as other columns
+	testCommonFunc(
+		t,
+		"SELECT `users`.`id`, `orders`.`id` AS `orders__id`, `orders`.`total` AS `orders__total` FROM `users` LEFT JOIN `orders` ON `users`.`user_id` = `orders`.`user_id`;",
+		NewSelectQueryBuilder().
+			Quote(QuoteAlways).
+			Table("users").
+			Join(LeftJoin, "orders", joinFields, joinFields).
+			ModelWithAlias(UserOrderID{}).
+			Build(),
+		true,
+	)
+}
+
+func Test_SelectQueryBuilder_ModelBeforeJoin(t *testing.T) {
+	type UserOrder struct {
+		UserID  string `db:"user_id" table:"users"`
+		OrderID string `db:"order_id" table:"orders"`
+	}
+	joinFields := []string{"user_id"}
+
+	tests := []struct {
+		name    string
+		builder func() *SelectQueryBuilder
+	}{
+		{"model", func() *SelectQueryBuilder {
+			return NewSelectQueryBuilder().Table("users").Model(UserOrder{})
+		}},
+		{"model with alias", func() *SelectQueryBuilder {
+			return NewSelectQueryBuilder().Table("users").ModelWithAlias(UserOrder{})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err := recover()
+				if err != ModelJoinOrderErr {
+					t.Log(err)
+					t.Fail()
+				}
+			}()
+			_ = tt.builder().Join(LeftJoin, "orders", joinFields, joinFields)
+		})
+	}
+
+	// join of table without model fields is allowed
+	testCommonFunc(
+		t,
+		"SELECT users.user_id FROM users LEFT JOIN tasks ON users.user_id = tasks.user_id;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Model(UserOrder{}).
+			Join(LeftJoin, "tasks", joinFields, joinFields).
+			Build(),
+		true,
+	)
+}
+
 func Test_SelectQueryBuilder_JoinMultipleFields(t *testing.T) {
 	fields := []string{"user_id", "task_id"}
 	q := NewSelectQueryBuilder().Table("users").
@@ -789,3 +950,1114 @@ func Test_SelectQueryBuilder_IsImmutable(t *testing.T) {
 		t.Log(qb, copied, " are deepEqual true. object is not immutable.")
 	}
 }
+
+func Test_SelectQueryBuilder_Lock(t *testing.T) {
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE user_id = ? FOR UPDATE;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Where("user_id", Equal).
+			ForUpdate().
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT jobs.* FROM jobs WHERE status = ? LIMIT ? FOR UPDATE SKIP LOCKED;",
+		NewSelectQueryBuilder().
+			Table("jobs").
+			Where("status", Equal).
+			Limit().
+			ForUpdate().
+			SkipLocked().
+			Build(),
+		false,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT jobs.* FROM jobs LEFT JOIN workers ON jobs.worker_id = workers.worker_id FOR NO KEY UPDATE OF jobs, workers NOWAIT;",
+		NewSelectQueryBuilder().
+			Dialect(PostgreSQL).
+			Table("jobs").
+			Join(LeftJoin, "workers", []string{"worker_id"}, []string{"worker_id"}).
+			ForNoKeyUpdate().
+			Of("jobs").
+			Of("workers").
+			NoWait().
+			Build(),
+		false,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT jobs.* FROM jobs WITH (UPDLOCK, READPAST) WHERE status = ?;",
+		NewSelectQueryBuilder().
+			Dialect(SQLServer).
+			Table("jobs").
+			Where("status", Equal).
+			ForUpdate().
+			SkipLocked().
+			Build(),
+		false,
+	)
+}
+
+func Test_SelectQueryBuilder_LockUnsupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  func() *SelectQueryBuilder
+		expected error
+	}{
+		{
+			name: "no key update is not supported by mysql",
+			builder: func() *SelectQueryBuilder {
+				return NewSelectQueryBuilder().Table("jobs").ForNoKeyUpdate()
+			},
+			expected: UnsupportedLockErr,
+		},
+		{
+			name: "of is not supported by sql server",
+			builder: func() *SelectQueryBuilder {
+				return NewSelectQueryBuilder().Dialect(SQLServer).Table("jobs").ForUpdate().Of("jobs")
+			},
+			expected: UnsupportedLockErr,
+		},
+		{
+			name: "skip locked requires lock strength",
+			builder: func() *SelectQueryBuilder {
+				return NewSelectQueryBuilder().Table("jobs").SkipLocked()
+			},
+			expected: LockStrengthRequiredErr,
+		},
+		{
+			name: "no wait and skip locked conflict",
+			builder: func() *SelectQueryBuilder {
+				return NewSelectQueryBuilder().Table("jobs").ForUpdate().NoWait().SkipLocked()
+			},
+			expected: LockWaitPolicyConflictErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err := recover()
+				if err != tt.expected {
+					t.Log(err)
+					t.Fail()
+				}
+			}()
+			_ = tt.builder().Build()
+		})
+	}
+}
+
+func Test_SelectQueryBuilder_Window(t *testing.T) {
+	rowNumber := Over("ROW_NUMBER()").PartitionBy("user_id").OrderBy("created", Desc)
+	testCommonFunc(
+		t,
+		"SELECT tasks.task_id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created DESC) AS row_num FROM tasks "+
+			"ORDER BY ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created DESC) ASC;",
+		NewSelectQueryBuilder().
+			Table("tasks").
+			Column("task_id").
+			WindowColumn(rowNumber, "row_num").
+			OrderByWindow(rowNumber, Asc).
+			Build(),
+		false,
+	)
+
+	// window in ORDER BY is quoted as window, not as comma separated columns
+	testCommonFunc(
+		t,
+		"SELECT `tasks`.`task_id` FROM `tasks` ORDER BY ROW_NUMBER() OVER (PARTITION BY `user_id`, `dept` ORDER BY `created` DESC) ASC;",
+		NewSelectQueryBuilder().
+			Strict().
+			Quote(QuoteAlways).
+			Table("tasks").
+			Column("task_id").
+			OrderByWindow(OverRaw(Raw("ROW_NUMBER()")).PartitionBy("user_id", "dept").OrderBy("created", Desc), Asc).
+			Build(),
+		false,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT orders.order_id, SUM(orders.total) OVER w AS running_total, RANK() OVER (w ORDER BY total DESC) AS total_rank FROM orders "+
+			"WINDOW w AS (PARTITION BY user_id ORDER BY created ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW);",
+		NewSelectQueryBuilder().
+			Table("orders").
+			Column("order_id").
+			WindowColumn(Over("SUM(orders.total)").Name("w"), "running_total").
+			WindowColumn(Over("RANK()").Name("w").OrderBy("total", Desc), "total_rank").
+			Window("w", NewWindow().PartitionBy("user_id").OrderBy("created", Asc).Rows(UnboundedPreceding, CurrentRow)).
+			Build(),
+		false,
+	)
+
+	// names and columns of window are quoted by quote mode of builder
+	testCommonFunc(
+		t,
+		"SELECT `orders`.`order_id`, RANK() OVER (PARTITION BY `order` ORDER BY `orders`.`total` DESC), SUM(total) OVER `w` AS `sum` FROM `orders` "+
+			"WINDOW `w` AS (PARTITION BY `user_id`);",
+		NewSelectQueryBuilder().
+			Table("orders").
+			Column("order_id").
+			WindowColumn(Over("RANK()").PartitionBy("order").OrderBy("orders.total", Desc), "").
+			WindowColumn(Over("SUM(total)").Name("w"), "sum").
+			Window("w", NewWindow().PartitionBy("user_id")).
+			Quote(QuoteAlways).
+			Build(),
+		false,
+	)
+}
+
+func Test_SelectQueryBuilder_Case(t *testing.T) {
+	statusLabel := Case().
+		When(Cond("status", Equal, "active_status"), "'active'").
+		When(Cond("status", IsNull).Or("deleted", Equal), "'deleted'").
+		Else("'inactive'")
+
+	testCommonFunc(
+		t,
+		"SELECT users.user_id, CASE WHEN status = ? THEN 'active' WHEN status IS NULL OR deleted = ? THEN 'deleted' ELSE 'inactive' END AS status_label FROM users "+
+			"WHERE age >= ? "+
+			"ORDER BY CASE WHEN sex = ? THEN 1 ELSE 2 END ASC;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Column("user_id").
+			CaseColumn(statusLabel, "status_label").
+			Where("age", GraterThanEqual).
+			OrderByCase(Case().When(Cond("sex", Equal), "1").Else("2"), Asc).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.user_id, CASE WHEN status = $1 THEN 'active' WHEN status IS NULL OR deleted = $2 THEN 'deleted' ELSE 'inactive' END AS status_label FROM users "+
+			"WHERE age >= $3 "+
+			"ORDER BY CASE WHEN sex = $4 THEN 1 ELSE 2 END ASC "+
+			"LIMIT $5;",
+		NewSelectQueryBuilder().
+			Placeholder(DollarNumber).
+			Table("users").
+			Column("user_id").
+			CaseColumn(statusLabel, "status_label").
+			Where("age", GraterThanEqual).
+			OrderByCase(Case().When(Cond("sex", Equal), "1").Else("2"), Asc).
+			Limit().
+			Build(),
+		false,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.user_id, CASE WHEN status = :active_status THEN 'active' WHEN status IS NULL OR deleted = :deleted THEN 'deleted' ELSE 'inactive' END AS status_label FROM users;",
+		NewSelectQueryBuilder().
+			Placeholder(Named).
+			Table("users").
+			Column("user_id").
+			CaseColumn(statusLabel, "status_label").
+			Build(),
+		true,
+	)
+
+	// alias same as column is rendered once as CASE
+	testCommonFunc(
+		t,
+		"SELECT users.status, CASE WHEN status = ? THEN 'active' WHEN status IS NULL OR deleted = ? THEN 'deleted' ELSE 'inactive' END AS status FROM users;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Column("status").
+			CaseColumn(statusLabel, "status").
+			Build(),
+		true,
+	)
+}
+
+func Test_SelectQueryBuilder_CaseWithoutWhen(t *testing.T) {
+	defer func() {
+		err := recover()
+		if err != CaseWhenRequiredErr {
+			t.Log(err)
+			t.Fail()
+		}
+	}()
+
+	_ = NewSelectQueryBuilder().
+		Table("users").
+		CaseColumn(Case().Else("1"), "one").
+		Build()
+}
+
+func Test_SelectQueryBuilder_CaseWithoutCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition *Condition
+	}{
+		{"nil", nil},
+		{"empty", AllOf()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				err := recover()
+				if err != CaseConditionRequiredErr {
+					t.Log(err)
+					t.Fail()
+				}
+			}()
+			_ = Case().When(tt.condition, "1")
+		})
+	}
+}
+
+func Test_SelectQueryBuilder_Quote(t *testing.T) {
+	testCommonFunc(
+		t,
+		"SELECT `users`.`user_id`, `users`.`order` FROM `users` "+
+			"LEFT JOIN `tasks` ON `users`.`user_id` = `tasks`.`user_id` "+
+			"WHERE `users`.`order` = ? "+
+			"GROUP BY `users`.`user_id` "+
+			"ORDER BY `created`, `user_id` ASC;",
+		NewSelectQueryBuilder().
+			Quote(QuoteAlways).
+			Table("users").
+			Column("user_id", "order").
+			Join(LeftJoin, "tasks", []string{"user_id"}, []string{"user_id"}).
+			Where("users.order", Equal, "order").
+			GroupBy("users.user_id").
+			OrderBy("created, user_id", Asc).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		`SELECT "Users".user_id, "Users"."order", COUNT(tasks.task_id) FROM public."Users" WHERE "user" = $1;`,
+		NewSelectQueryBuilder().
+			Dialect(PostgreSQL).
+			Placeholder(DollarNumber).
+			Quote(QuoteWhenNeeded).
+			Table("public.Users").
+			Column("Users.user_id", "Users.order").
+			ColumnRaw(Raw("COUNT(tasks.task_id)")).
+			Where("user", Equal).
+			Build(),
+		false,
+	)
+
+	// function column without Raw is not quoted as identifier
+	testCommonFunc(
+		t,
+		"SELECT `users`.`user_id`, COUNT(*) FROM `users` GROUP BY `users`.`user_id`;",
+		NewSelectQueryBuilder().
+			Quote(QuoteAlways).
+			Table("users").
+			Column("user_id", "COUNT(*)").
+			GroupBy("users.user_id").
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT `users`.`user_id` FROM `users` WHERE LOWER(name) = ?;",
+		NewSelectQueryBuilder().
+			Quote(QuoteAlways).
+			Table("users").
+			Column("user_id").
+			Where("LOWER(name)", Equal, "name").
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT `users`.`user_id` FROM `users` GROUP BY `users`.`user_id`, DATE(created) ORDER BY COALESCE(nickname, name), `user_id` ASC;",
+		NewSelectQueryBuilder().
+			Quote(QuoteAlways).
+			Table("users").
+			Column("user_id").
+			GroupBy("users.user_id, DATE(created)").
+			OrderBy("COALESCE(nickname, name), user_id", Asc).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		`SELECT "jobs".* FROM "jobs" FOR UPDATE OF "jobs", LOWER(workers);`,
+		NewSelectQueryBuilder().
+			Dialect(PostgreSQL).
+			Quote(QuoteAlways).
+			Table("jobs").
+			ForUpdate().
+			Of("jobs", "LOWER(workers)").
+			Build(),
+		false,
+	)
+}
+
+func Test_SelectQueryBuilder_QuoteSpace(t *testing.T) {
+	testCommonFunc(
+		t,
+		"SELECT `users`.`first name` FROM `users` WHERE `users`.`last name` = ? ORDER BY `first name` ASC;",
+		NewSelectQueryBuilder().
+			Quote(QuoteAlways).
+			Table("users").
+			Column("first name").
+			Where("users.last name", Equal, "last_name").
+			OrderBy("first name", Asc).
+			Build(),
+		false,
+	)
+}
+
+func Test_SelectQueryBuilder_Raw(t *testing.T) {
+	columns := []string{"user_id", "name"}
+	testCommonFunc(
+		t,
+		"SELECT users.user_id, users.name, COUNT(*) AS cnt FROM users WHERE LOWER(name) = ? OR LOWER(email) = ? OR UPPER(code) = ? GROUP BY users.user_id, users.name;",
+		NewSelectQueryBuilder().
+			Strict().
+			Table("users").
+			Column(columns...).
+			ColumnRaw(Raw("COUNT(*) AS cnt")).
+			WhereRaw(Raw("LOWER(name)"), Equal, "name").
+			OrRaw(Raw("LOWER(email)"), Equal, "email").
+			OrCondition(CondRaw(Raw("UPPER(code)"), Equal, "code")).
+			GroupBy("users.user_id, users.name").
+			Build(),
+		true,
+	)
+}
+
+func Test_SelectQueryBuilder_Strict(t *testing.T) {
+	testCommonFunc(
+		t,
+		"SELECT users.user_id, COUNT(*) AS cnt FROM users WHERE LOWER(name) = ? GROUP BY users.user_id ORDER BY cnt DESC;",
+		NewSelectQueryBuilder().
+			Strict().
+			Table("users").
+			Column("user_id").
+			ColumnRaw(Raw("COUNT(*) AS cnt")).
+			WhereRaw(Raw("LOWER(name)"), Equal, "name").
+			GroupBy("users.user_id").
+			OrderBy("cnt", Desc).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT DATE(created) AS day FROM users GROUP BY DATE(created) ORDER BY COUNT(*) DESC;",
+		NewSelectQueryBuilder().
+			Strict().
+			Table("users").
+			ColumnRaw(Raw("DATE(created) AS day")).
+			GroupByRaw(Raw("DATE(created)")).
+			OrderByRaw(Raw("COUNT(*)"), Desc).
+			Build(),
+		true,
+	)
+
+	// literal and identifier results, and Raw function and frame constants are accepted
+	testCommonFunc(
+		t,
+		"SELECT CASE WHEN status = ? THEN 'active' WHEN deleted = ? THEN users.name ELSE NULL END AS label, "+
+			"SUM(total) OVER (ORDER BY created ASC ROWS BETWEEN 2 PRECEDING AND CURRENT ROW) AS running_total FROM users;",
+		NewSelectQueryBuilder().
+			Strict().
+			Table("users").
+			CaseColumn(Case().When(Cond("status", Equal), "'active'").When(Cond("deleted", Equal), "users.name").Else("NULL"), "label").
+			WindowColumn(OverRaw(Raw("SUM(total)")).OrderBy("created", Asc).Rows("2 PRECEDING", CurrentRow), "running_total").
+			Build(),
+		false,
+	)
+
+	tests := []struct {
+		name     string
+		builder  *SelectQueryBuilder
+		expected error
+	}{
+		{
+			name:     "expression without raw",
+			builder:  NewSelectQueryBuilder().Table("users").Column("COUNT(*) AS cnt"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled order by",
+			builder:  NewSelectQueryBuilder().Table("users").OrderBy("created; DROP TABLE users", Asc),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled order direction",
+			builder:  NewSelectQueryBuilder().Table("users").OrderBy("created", "ASC, (SELECT 1)"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled where column",
+			builder:  NewSelectQueryBuilder().Table("users").Where("1 = 1 OR name", Equal),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled named bind",
+			builder:  NewSelectQueryBuilder().Placeholder(Named).Table("users").Where("name", Equal, "name OR 1 = 1"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled join",
+			builder:  NewSelectQueryBuilder().Table("users").Join(LeftJoin, "tasks --", []string{"user_id"}, []string{"user_id"}),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled operator",
+			builder:  NewSelectQueryBuilder().Table("users").Where("id", "= 1 OR 1=1 OR id ="),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled join type",
+			builder:  NewSelectQueryBuilder().Table("users").Join("LEFT JOIN secrets ON 1=1 CROSS JOIN", "tasks", []string{"user_id"}, []string{"user_id"}),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled limit bind",
+			builder:  NewSelectQueryBuilder().Placeholder(Named).Table("users").Limit("x; DROP TABLE users --"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled offset bind",
+			builder:  NewSelectQueryBuilder().Placeholder(Named).Table("users").Limit().Offset("x OR 1=1"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "raw prefix in string",
+			builder:  NewSelectQueryBuilder().Table("users").Column("\x00raw:(SELECT password FROM admins) AS x"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "same string as raw",
+			builder:  NewSelectQueryBuilder().Table("users").ColumnRaw(Raw("COUNT(*)")).OrderBy("COUNT(*)", Asc),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled window partition",
+			builder:  NewSelectQueryBuilder().Table("users").WindowColumn(Over("RANK()").PartitionBy("(SELECT password FROM admins)"), "r"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled window order",
+			builder:  NewSelectQueryBuilder().Table("users").OrderByWindow(Over("RANK()").OrderBy("score, (SELECT 1)", Desc), Asc),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled window order direction",
+			builder:  NewSelectQueryBuilder().Table("users").WindowColumn(Over("RANK()").OrderBy("score", "DESC, (SELECT 1)"), "r"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled window definition",
+			builder:  NewSelectQueryBuilder().Table("users").Window("w", NewWindow().PartitionBy("user_id; --")),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "comment in raw",
+			builder:  NewSelectQueryBuilder().Table("users").ColumnRaw(Raw("COUNT(*) -- ")),
+			expected: SuspiciousExpressionErr,
+		},
+		{
+			name:     "unbalanced quote in case",
+			builder:  NewSelectQueryBuilder().Table("users").CaseColumn(Case().WhenRaw(Cond("status", Equal), Raw("'active")), "label"),
+			expected: SuspiciousExpressionErr,
+		},
+		{
+			name:     "subquery in case result",
+			builder:  NewSelectQueryBuilder().Table("users").CaseColumn(Case().When(Cond("a", Equal), "(SELECT password FROM admins LIMIT 1)"), "x"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled case else",
+			builder:  NewSelectQueryBuilder().Table("users").CaseColumn(Case().When(Cond("a", Equal), "1").Else("0 END, password"), "x"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "window function without raw",
+			builder:  NewSelectQueryBuilder().Table("users").WindowColumn(Over("(SELECT password FROM admins)"), "r"),
+			expected: InvalidIdentifierErr,
+		},
+		{
+			name:     "smuggled window frame",
+			builder:  NewSelectQueryBuilder().Table("users").WindowColumn(OverRaw(Raw("RANK()")).Rows("(SELECT 1) PRECEDING", CurrentRow), "r"),
+			expected: InvalidIdentifierErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.builder.Validate(); !errors.Is(err, tt.expected) {
+				t.Logf("expected: %v, actual: %v", tt.expected, err)
+				t.Fail()
+			}
+
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, tt.expected) {
+					t.Logf("expected: %v, actual: %v", tt.expected, err)
+					t.Fail()
+				}
+			}()
+			_ = tt.builder.Strict().Build()
+		})
+	}
+}
+
+func Test_SelectQueryBuilder_OrderByAllowed(t *testing.T) {
+	allowed := map[string]string{
+		"createdAt": "users.created_at",
+		"name":      "users.name",
+	}
+
+	qb, err := NewSelectQueryBuilder().
+		Table("users").
+		OrderByAllowed("-createdAt,name", allowed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users ORDER BY users.created_at DESC, users.name ASC LIMIT ?;",
+		qb.Limit().Build(),
+		true,
+	)
+
+	_, err = NewSelectQueryBuilder().
+		Table("users").
+		OrderByAllowed("-password", allowed)
+	var unknown *UnknownSortFieldError
+	if !errors.As(err, &unknown) || unknown.Field != "password" {
+		t.Logf("expected UnknownSortFieldError, actual: %v", err)
+		t.Fail()
+	}
+}
+
+func Test_SelectQueryBuilder_WhereMultiByStructExtendedOperator(t *testing.T) {
+	type SearchUsersParameter struct {
+		UserIDs     []string    `db:"user_id" search:"user_ids" operator:"in"`
+		ExcludeSex  []string    `db:"sex" search:"exclude_sex" operator:"not-in"`
+		AgeRange    *[2]int     `db:"age" search:"age_range" operator:"between"`
+		NamePrefix  *string     `db:"name" search:"name_prefix" operator:"prefix"`
+		EmailSuffix *string     `db:"email" search:"email_suffix" operator:"suffix"`
+		Keyword     *string     `db:"profile" search:"keyword" operator:"contains"`
+		Created     []time.Time `db:"created" search:"created" operator:"between"`
+	}
+
+	prefix, suffix, keyword := "tre", "@example.com", "go"
+	searchParam := SearchUsersParameter{
+		UserIDs:     []string{"1", "2", "3"},
+		ExcludeSex:  []string{"unknown"},
+		AgeRange:    &[2]int{20, 30},
+		NamePrefix:  &prefix,
+		EmailSuffix: &suffix,
+		Keyword:     &keyword,
+	}
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users "+
+			"WHERE user_id IN (:user_ids1, :user_ids2, :user_ids3) "+
+			"AND sex NOT IN (:exclude_sex1) "+
+			"AND age BETWEEN :age_range1 AND :age_range2 "+
+			"AND name LIKE :name_prefix ESCAPE '\\\\' "+
+			"AND email LIKE :email_suffix ESCAPE '\\\\' "+
+			"AND profile LIKE :keyword ESCAPE '\\\\';",
+		NewSelectQueryBuilder().
+			Placeholder(Named).
+			Table("users").
+			WhereMultiByStruct(searchParam).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users "+
+			"WHERE user_id IN ($1, $2, $3) "+
+			"AND sex NOT IN ($4) "+
+			"AND age BETWEEN $5 AND $6 "+
+			"AND name LIKE $7 ESCAPE '\\\\' "+
+			"AND email LIKE $8 ESCAPE '\\\\' "+
+			"AND profile LIKE $9 ESCAPE '\\\\';",
+		NewSelectQueryBuilder().
+			Placeholder(DollarNumber).
+			Table("users").
+			WhereMultiByStruct(searchParam).
+			Build(),
+		false,
+	)
+}
+
+func Test_SelectQueryBuilder_WhereMultiByStructArgs(t *testing.T) {
+	type SearchUsersParameter struct {
+		AgeRange   []int   `db:"age" search:"age_range" operator:"between"`
+		NamePrefix *string `db:"name" search:"name_prefix" operator:"prefix"`
+		Keyword    *string `db:"profile" search:"keyword" operator:"contains"`
+	}
+
+	prefix, keyword := "50%", "a[b"
+	// between of 3 values is skipped same as empty list
+	builder := NewSelectQueryBuilder().
+		Table("users").
+		WhereMultiByStruct(SearchUsersParameter{AgeRange: []int{1, 2, 3}, NamePrefix: &prefix, Keyword: &keyword})
+
+	testCommonFunc(
+		t,
+		`SELECT users.* FROM users WHERE name LIKE $1 ESCAPE '\' AND profile LIKE $2 ESCAPE '\';`,
+		builder.Dialect(PostgreSQL).Placeholder(DollarNumber).Build(),
+		false,
+	)
+
+	testCommonFunc(
+		t,
+		`SELECT users.* FROM users WHERE name LIKE ? ESCAPE '\' AND profile LIKE ? ESCAPE '\';`,
+		builder.Dialect(SQLServer).Build(),
+		false,
+	)
+
+	args, err := builder.Args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{`50\%%`, `%a\[b%`}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+}
+
+func Test_SelectQueryBuilder_WhereMultiByStructLikePatternNotString(t *testing.T) {
+	type SearchUsersParameter struct {
+		AgePrefix *int `db:"age" search:"age_prefix" operator:"prefix"`
+	}
+
+	defer func() {
+		if err := recover(); err != "*int operator field should be string" {
+			t.Logf("expected panic, actual: %v", err)
+			t.Fail()
+		}
+	}()
+
+	age := 2
+	_ = NewSelectQueryBuilder().
+		Table("users").
+		WhereMultiByStruct(SearchUsersParameter{AgePrefix: &age})
+}
+
+func Test_LikePattern(t *testing.T) {
+	tests := []struct {
+		operatorTag string
+		value       string
+		expected    string
+	}{
+		{"prefix", "50%", `50\%%`},
+		{"suffix", "a_b", `%a\_b`},
+		{"contains", `c:\dir`, `%c:\\dir%`},
+		{"contains", "[a]", `%\[a]%`},
+		{"eq", "50%", "50%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operatorTag, func(t *testing.T) {
+			if err := checkQuery(tt.expected, LikePattern(tt.operatorTag, tt.value)); err != nil {
+				t.Log(err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func Test_SelectQueryBuilder_WhereMultiByStructNullCheck(t *testing.T) {
+	type SearchTasksParameter struct {
+		Title      *string `db:"title" search:"title" operator:"eq"`
+		Unassigned *bool   `db:"assignee_id" search:"unassigned" operator:"is-null"`
+		Finished   *bool   `db:"finished_at" search:"finished" operator:"not-null"`
+		Archived   *bool   `db:"archived_at" search:"archived" operator:"is-null"`
+	}
+
+	title, yes, no := "task", true, false
+	tests := []struct {
+		name     string
+		param    SearchTasksParameter
+		expected string
+	}{
+		{
+			name:     "true",
+			param:    SearchTasksParameter{Title: &title, Unassigned: &yes, Finished: &yes},
+			expected: "SELECT tasks.* FROM tasks WHERE title = $1 AND assignee_id IS NULL AND finished_at IS NOT NULL;",
+		},
+		{
+			name:     "false",
+			param:    SearchTasksParameter{Unassigned: &no, Finished: &no, Title: &title},
+			expected: "SELECT tasks.* FROM tasks WHERE title = $1 AND assignee_id IS NOT NULL AND finished_at IS NULL;",
+		},
+		{
+			name:     "false only",
+			param:    SearchTasksParameter{Archived: &no},
+			expected: "SELECT tasks.* FROM tasks WHERE archived_at IS NOT NULL;",
+		},
+		{
+			name:     "nil",
+			param:    SearchTasksParameter{Unassigned: nil, Finished: (*bool)(nil), Archived: &yes},
+			expected: "SELECT tasks.* FROM tasks WHERE archived_at IS NULL;",
+		},
+		{
+			name:     "all nil",
+			param:    SearchTasksParameter{},
+			expected: "SELECT tasks.* FROM tasks;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCommonFunc(
+				t,
+				tt.expected,
+				NewSelectQueryBuilder().
+					Placeholder(DollarNumber).
+					Table("tasks").
+					WhereMultiByStruct(tt.param).
+					Build(),
+				false,
+			)
+		})
+	}
+
+	for _, info := range newQueryBuilder().buildBindMap(SearchTag, SearchTasksParameter{Unassigned: &yes}) {
+		if info["bind"] != "" {
+			t.Logf("bind should not be generated for null predicate. actual: %s", info["bind"])
+			t.Fail()
+		}
+	}
+}
+
+func Test_SelectQueryBuilder_WhereMultiByStructRepeatedBind(t *testing.T) {
+	type SearchUsersParameter struct {
+		AgeFrom *int    `db:"age" search:"age" operator:"gte"`
+		Name    *string `db:"name" search:"name" operator:"eq"`
+		AgeTo   *int    `db:"age_limit" search:"age" operator:"lte"`
+	}
+
+	from, to, name := 20, 30, "trewanek"
+	tests := []struct {
+		name         string
+		param        SearchUsersParameter
+		expected     string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "last field in first position",
+			param:        SearchUsersParameter{AgeFrom: &from, Name: &name, AgeTo: &to},
+			expected:     "SELECT users.* FROM users WHERE age_limit <= ? AND name = ?;",
+			expectedArgs: []interface{}{30, "trewanek"},
+		},
+		{
+			name:         "nil field is not counted",
+			param:        SearchUsersParameter{AgeFrom: &from, Name: &name},
+			expected:     "SELECT users.* FROM users WHERE age >= ? AND name = ?;",
+			expectedArgs: []interface{}{20, "trewanek"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewSelectQueryBuilder().Table("users").WhereMultiByStruct(tt.param)
+			testCommonFunc(t, tt.expected, builder.Build(), false)
+
+			args, err := builder.Args()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.expectedArgs, args) {
+				t.Logf("expected: %v, actual: %v", tt.expectedArgs, args)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func Test_SelectQueryBuilder_WhereMultiByStructGroup(t *testing.T) {
+	type PeriodParameter struct {
+		CreatedFrom *time.Time `db:"created" search:"created_from" operator:"gte"`
+		CreatedTo   *time.Time `db:"created" search:"created_to" operator:"lt"`
+	}
+	type SearchUsersParameter struct {
+		Status  *string          `db:"status" search:"status" operator:"eq"`
+		Name    *string          `db:"name" search:"keyword_name" operator:"contains" group:"keyword" logic:"or"`
+		Email   *string          `db:"email" search:"keyword_email" operator:"contains" group:"keyword"`
+		Phone   *string          `db:"phone" search:"keyword_phone" operator:"prefix" group:"keyword"`
+		Period  *PeriodParameter `logic:"and"`
+		Periods struct {
+			Registered PeriodParameter
+			Updated    struct {
+				UpdatedFrom *time.Time `db:"updated" search:"updated_from" operator:"gte"`
+				Unupdated   *bool      `db:"updated" search:"unupdated" operator:"is-null"`
+			} `logic:"or"`
+		} `logic:"or"`
+	}
+
+	status, keyword, yes := "active", "tre", true
+	now := time.Now()
+
+	param := SearchUsersParameter{Status: &status, Name: &keyword, Email: &keyword, Phone: &keyword}
+	param.Period = &PeriodParameter{CreatedFrom: &now, CreatedTo: &now}
+	param.Periods.Registered = PeriodParameter{CreatedFrom: &now, CreatedTo: &now}
+	param.Periods.Updated.UpdatedFrom = &now
+	param.Periods.Updated.Unupdated = &yes
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users "+
+			"WHERE status = :status "+
+			"AND (name LIKE :keyword_name ESCAPE '\\\\' OR email LIKE :keyword_email ESCAPE '\\\\' OR phone LIKE :keyword_phone ESCAPE '\\\\') "+
+			"AND (created >= :created_from AND created < :created_to) "+
+			"AND ((created >= :created_from AND created < :created_to) OR (updated >= :updated_from OR updated IS NULL));",
+		NewSelectQueryBuilder().
+			Placeholder(Named).
+			Table("users").
+			WhereMultiByStruct(param).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users "+
+			"WHERE deleted = ? "+
+			"AND name LIKE ? ESCAPE '\\\\' "+
+			"AND (updated >= ? OR updated IS NULL);",
+		NewSelectQueryBuilder().
+			Table("users").
+			Where("deleted", Equal).
+			WhereMultiByStruct(struct {
+				Name    *string `db:"name" search:"keyword_name" operator:"contains" group:"keyword" logic:"or"`
+				Updated struct {
+					UpdatedFrom *time.Time `db:"updated" search:"updated_from" operator:"gte"`
+					Unupdated   *bool      `db:"updated" search:"unupdated" operator:"is-null"`
+				} `logic:"or"`
+			}{Name: &keyword, Updated: param.Periods.Updated}).
+			Build(),
+		true,
+	)
+}
+
+func Test_SelectQueryBuilder_BuildWithArgs(t *testing.T) {
+	type SearchUsersParameter struct {
+		Status   *string  `db:"status" search:"status" operator:"eq"`
+		Name     *string  `db:"name" search:"keyword_name" operator:"contains" group:"keyword" logic:"or"`
+		Deleted  *bool    `db:"deleted_at" search:"deleted" operator:"is-null"`
+		UserIDs  []int    `db:"user_id" search:"user_ids" operator:"in"`
+		Email    *string  `db:"email" search:"keyword_email" operator:"prefix" group:"keyword"`
+		AgeRange *[2]*int `db:"age" search:"age_range" operator:"between"`
+		Unknown  *string  `db:"unknown" search:"unknown" operator:"unknown"`
+		Nested   struct {
+			Sex *string `db:"sex" search:"sex" operator:"ne"`
+		}
+	}
+
+	status, keyword, sex, deleted, unknown := "active", "50%", "unknown", false, "x"
+	from, to := 20, 30
+	searchParam := SearchUsersParameter{
+		Status:   &status,
+		Name:     &keyword,
+		Deleted:  &deleted,
+		UserIDs:  []int{1, 2},
+		Email:    &keyword,
+		AgeRange: &[2]*int{&from, &to},
+		Unknown:  &unknown,
+	}
+	searchParam.Nested.Sex = &sex
+
+	builder := NewSelectQueryBuilder().
+		Placeholder(DollarNumber).
+		Table("users").
+		WhereMultiByStruct(&searchParam)
+
+	query, args, err := builder.BuildWithArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users "+
+			"WHERE status = $1 "+
+			"AND (name LIKE $2 ESCAPE '\\\\' OR email LIKE $3 ESCAPE '\\\\') "+
+			"AND deleted_at IS NOT NULL "+
+			"AND user_id IN ($4, $5) "+
+			"AND age BETWEEN $6 AND $7 "+
+			"AND sex != $8;",
+		query,
+		false,
+	)
+
+	namedArgs, err := builder.NamedArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedArgs := []interface{}{"active", `%50\%%`, `50\%%`, 1, 2, 20, 30, "unknown"}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+
+	expectedNamedArgs := map[string]interface{}{
+		"status":        "active",
+		"keyword_name":  `%50\%%`,
+		"keyword_email": `50\%%`,
+		"user_ids1":     1,
+		"user_ids2":     2,
+		"age_range1":    20,
+		"age_range2":    30,
+		"sex":           "unknown",
+	}
+	if !reflect.DeepEqual(expectedNamedArgs, namedArgs) {
+		t.Logf("expected: %v, actual: %v", expectedNamedArgs, namedArgs)
+		t.Fail()
+	}
+}
+
+func Test_SelectQueryBuilder_WhereInValues(t *testing.T) {
+	builder := NewSelectQueryBuilder().
+		Table("users").
+		Where("status", Equal).
+		WhereInValues("user_id", []int{1, 2, 3}, "user_ids").
+		WhereNotInValues("sex", []string{}).
+		Limit()
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE status = $1 AND user_id IN ($2, $3, $4) AND 1=1 LIMIT $5;",
+		builder.Placeholder(DollarNumber).Build(),
+		false,
+	)
+
+	args, err := builder.Args(map[string]interface{}{"status": "active", "limit": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{"active", 1, 2, 3, 10}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE user_id IN (:user_ids1, :user_ids2) AND 1=0;",
+		NewSelectQueryBuilder().
+			Placeholder(Named).
+			Table("users").
+			WhereInValues("user_id", &[2]string{"1", "2"}, "user_ids").
+			WhereInValues("sex", []string(nil)).
+			Build(),
+		true,
+	)
+
+	if _, err := builder.Args(); !errors.Is(err, BindNotFoundErr) {
+		t.Logf("expected: %v, actual: %v", BindNotFoundErr, err)
+		t.Fail()
+	}
+}
+
+func Test_SelectQueryBuilder_ArrayBinding(t *testing.T) {
+	builder := NewSelectQueryBuilder().
+		Dialect(PostgreSQL).
+		ArrayBinding().
+		Table("users").
+		WhereInValues("user_id", []int{1, 2, 3}, "user_ids").
+		WhereNotInValues("sex", []string{}).
+		WhereIn("status", 2)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users WHERE user_id = ANY($1) AND sex <> ALL($2) AND status IN ($3, $4);",
+		builder.Placeholder(DollarNumber).Build(),
+		false,
+	)
+
+	args, err := builder.Args(map[string]interface{}{"status": []string{"active", "pending"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{[]int{1, 2, 3}, []string{}, "active", "pending"}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+
+	defer func() {
+		if err := recover(); err != UnsupportedArrayBindingErr {
+			t.Logf("expected: %v, actual: %v", UnsupportedArrayBindingErr, err)
+			t.Fail()
+		}
+	}()
+	builder.Dialect(MySQL).Build()
+}
+
+type tableNamedUser struct {
+	UserID string `db:"user_id"`
+	Name   string `db:"name"`
+	Memo   string `db:"memo" table:"memos"`
+}
+
+func (*tableNamedUser) TableName() string {
+	return "users"
+}
+
+func Test_SelectQueryBuilder_ModelTableName(t *testing.T) {
+	testCommonFunc(
+		t,
+		"SELECT users.user_id, users.name FROM users;",
+		NewSelectQueryBuilder().
+			Model(tableNamedUser{}).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"INSERT INTO tasks(task_id, title) VALUES(?, ?);",
+		NewInsertQueryBuilder().
+			Model(struct {
+				_      struct{} `table:"tasks"`
+				TaskID string   `db:"task_id"`
+				Title  string   `db:"title"`
+			}{TaskID: "t1", Title: "title"}).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT memos.memo FROM memos;",
+		NewSelectQueryBuilder().
+			Table("memos").
+			Model(&tableNamedUser{}).
+			Build(),
+		true,
+	)
+
+	defer func() {
+		if err := recover(); err != ModelColumnNotFoundErr {
+			t.Logf("expected: %v, actual: %v", ModelColumnNotFoundErr, err)
+			t.Fail()
+		}
+	}()
+	NewSelectQueryBuilder().Model(User{}).Build()
+}
@@ -0,0 +1,113 @@
+package query_builder
+
+import (
+	"sync"
+	"time"
+)
+
+// table => soft delete column
+var (
+	softDeleteColumns      = make(map[string]string)
+	softDeleteColumnsMutex sync.RWMutex
+)
+
+// trashed rows mode
+const (
+	withoutTrashed = iota
+	withTrashed
+	onlyTrashed
+)
+
+// rows of table are soft deleted by column. ex. SetSoftDelete("users", "deleted_at")
+// Select and Update exclude deleted rows(joined tables too), Delete renders UPDATE {table} SET {column} = CURRENT_TIMESTAMP.
+// empty column unsets.
+func SetSoftDelete(table, column string) {
+	softDeleteColumnsMutex.Lock()
+	defer softDeleteColumnsMutex.Unlock()
+	table = normalizeTableName(table)
+	if column == "" {
+		delete(softDeleteColumns, table)
+		return
+	}
+	softDeleteColumns[table] = column
+}
+
+func getSoftDeleteColumn(table string) string {
+	softDeleteColumnsMutex.RLock()
+	defer softDeleteColumnsMutex.RUnlock()
+	return softDeleteColumns[normalizeTableName(table)]
+}
+
+func (builder *queryBuilder) softDelete(column string) *queryBuilder {
+	copied := builder.copy()
+	copied.trashColumn = column
+	return copied
+}
+
+func (builder *queryBuilder) setTrashed(mode int) *queryBuilder {
+	copied := builder.copy()
+	copied.trashed = mode
+	return copied
+}
+
+// column of builder takes priority over SetSoftDelete
+func (builder *queryBuilder) getSoftDeleteColumn() string {
+	if builder.trashColumn != "" {
+		return builder.trashColumn
+	}
+	return getSoftDeleteColumn(builder.tableName)
+}
+
+// column of JoinSoftDelete takes priority over SetSoftDelete of joined table
+func getJoinSoftDeleteColumn(join map[string]interface{}) string {
+	if column, ok := join["softDelete"].(string); ok {
+		return column
+	}
+	return getSoftDeleteColumn(join["table"].(string))
+}
+
+// {column} IS NULL, or {column} IS NOT NULL by OnlyTrashed
+func (builder *queryBuilder) softDeleteScope(qualified bool) *queryBuilder {
+	column := builder.getSoftDeleteColumn()
+	if column == "" || builder.trashed == withTrashed {
+		return builder
+	}
+
+	if qualified {
+		column = builder.tableName + "." + column
+	}
+	operator := IsNull
+	if builder.trashed == onlyTrashed {
+		operator = IsNotNull
+	}
+	return builder.guard(Cond(column, operator))
+}
+
+// {column} is CURRENT_TIMESTAMP, so positional args of WHERE are not shifted.
+// Now of Audit or DefaultAudit is bound instead when set. audit columns of update are set together
+func (builder *queryBuilder) softDeleted() *queryBuilder {
+	column := builder.getSoftDeleteColumn()
+	if column == "" {
+		return builder
+	}
+
+	copied := builder.copy()
+	copied.columns = []string{column}
+	copied.values = builder.getNamedArgs(nil)
+	copied.expressions = copyStringMap(builder.expressions)
+	if now, ok := builder.now(); ok {
+		copied.values[column] = now
+	} else {
+		copied.expressions[column] = "CURRENT_TIMESTAMP"
+	}
+	return copied.audited(modelUpdate)
+}
+
+// Now of AuditColumns for fixed clock. false when Now is not set
+func (builder *queryBuilder) now() (time.Time, bool) {
+	columns := builder.getAuditColumns()
+	if columns != nil && columns.Now != nil {
+		return columns.Now(), true
+	}
+	return time.Time{}, false
+}
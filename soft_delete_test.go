@@ -0,0 +1,256 @@
+package query_builder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_SelectQueryBuilder_SoftDelete(t *testing.T) {
+	SetSoftDelete("users", "deleted_at")
+	SetSoftDelete("tasks", "deleted_at")
+	defer SetSoftDelete("users", "")
+	defer SetSoftDelete("tasks", "")
+
+	joinFields := []string{"user_id"}
+	builder := NewSelectQueryBuilder().
+		Table("users").
+		Join(LeftJoin, "tasks", joinFields, joinFields).
+		Where("name", Equal).
+		Or("email", Equal)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users LEFT JOIN tasks ON users.user_id = tasks.user_id AND tasks.deleted_at IS NULL WHERE (name = ? OR email = ?) AND users.deleted_at IS NULL;",
+		builder.Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users LEFT JOIN tasks ON users.user_id = tasks.user_id AND tasks.deleted_at IS NULL WHERE (name = ? OR email = ?) AND users.deleted_at IS NOT NULL;",
+		builder.OnlyTrashed().Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users LEFT JOIN tasks ON users.user_id = tasks.user_id WHERE name = ? OR email = ?;",
+		builder.WithTrashed().Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT groups.* FROM groups WHERE groups.removed_at IS NULL;",
+		NewSelectQueryBuilder().Table("groups").SoftDelete("removed_at").Build(),
+		true,
+	)
+
+	// table is looked up without schema, quotes and case
+	testCommonFunc(
+		t,
+		"SELECT public.Users.* FROM public.Users WHERE public.Users.deleted_at IS NULL;",
+		NewSelectQueryBuilder().Table("public.Users").Build(),
+		true,
+	)
+
+	// column of builder is not used for joined tables, JoinSoftDelete sets column of joined table
+	testCommonFunc(
+		t,
+		"SELECT groups.* FROM groups INNER JOIN prefectures ON groups.prefecture_id = prefectures.prefecture_id INNER JOIN members ON groups.group_id = members.group_id AND members.removed_at IS NULL WHERE groups.removed_at IS NULL;",
+		NewSelectQueryBuilder().
+			Table("groups").
+			SoftDelete("removed_at").
+			Join(InnerJoin, "prefectures", []string{"prefecture_id"}, []string{"prefecture_id"}).
+			Join(InnerJoin, "members", []string{"group_id"}, []string{"group_id"}).
+			JoinSoftDelete("members", "removed_at").
+			Build(),
+		true,
+	)
+
+	// empty column disables SetSoftDelete of joined table
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users LEFT JOIN tasks ON users.user_id = tasks.user_id WHERE users.deleted_at IS NULL;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Join(LeftJoin, "tasks", joinFields, joinFields).
+			JoinSoftDelete("tasks", "").
+			Build(),
+		true,
+	)
+
+	// ON of RIGHT JOIN doesn't exclude deleted rows of joined table, so condition is in WHERE
+	testCommonFunc(
+		t,
+		"SELECT users.* FROM users RIGHT JOIN tasks ON users.user_id = tasks.user_id WHERE (name = ? OR email = ?) AND tasks.deleted_at IS NULL AND users.deleted_at IS NULL;",
+		NewSelectQueryBuilder().
+			Table("users").
+			Join(RightJoin, "tasks", joinFields, joinFields).
+			Where("name", Equal).
+			Or("email", Equal).
+			Build(),
+		true,
+	)
+}
+
+func Test_UpdateQueryBuilder_SoftDelete(t *testing.T) {
+	builder := NewUpdateQueryBuilder().
+		SoftDelete("deleted_at").
+		Table("users").
+		Column("name").
+		Where("user_id", Equal)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ? WHERE user_id = ? AND deleted_at IS NULL;",
+		builder.Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ? WHERE user_id = ? AND deleted_at IS NOT NULL;",
+		builder.OnlyTrashed().Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ? WHERE user_id = ?;",
+		builder.WithTrashed().Build(),
+		true,
+	)
+}
+
+func Test_DeleteQueryBuilder_SoftDelete(t *testing.T) {
+	SetSoftDelete("users", "deleted_at")
+	defer SetSoftDelete("users", "")
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	SetDefaultAuditColumns(&AuditColumns{Now: func() time.Time { return now }})
+	defer SetDefaultAuditColumns(nil)
+
+	builder := NewDeleteQueryBuilder().
+		DefaultAudit().
+		Table("users").
+		Where("user_id", Equal)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET deleted_at = $1 WHERE user_id = $2 AND deleted_at IS NULL;",
+		builder.Placeholder(DollarNumber).Build(),
+		false,
+	)
+
+	args, err := builder.Args(map[string]interface{}{"user_id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{now, 1}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+
+	testCommonFunc(
+		t,
+		"DELETE FROM users WHERE user_id = ?;",
+		builder.HardDelete().Build(),
+		true,
+	)
+
+	args, err = builder.HardDelete().Args(map[string]interface{}{"user_id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]interface{}{1}, args) {
+		t.Logf("expected: %v, actual: %v", []interface{}{1}, args)
+		t.Fail()
+	}
+}
+
+// positional args of existing call sites are not shifted by soft delete
+func Test_DeleteQueryBuilder_SoftDeleteCurrentTimestamp(t *testing.T) {
+	SetSoftDelete("users", "deleted_at")
+	defer SetSoftDelete("users", "")
+
+	// clock of SetDefaultAuditColumns is not used without DefaultAudit
+	SetDefaultAuditColumns(&AuditColumns{Now: time.Now})
+	defer SetDefaultAuditColumns(nil)
+
+	builder := NewDeleteQueryBuilder().
+		Table("users").
+		Where("user_id", Equal)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE user_id = ? AND deleted_at IS NULL;",
+		builder.Build(),
+		true,
+	)
+
+	args, err := builder.Args(map[string]interface{}{"user_id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]interface{}{1}, args) {
+		t.Logf("expected: %v, actual: %v", []interface{}{1}, args)
+		t.Fail()
+	}
+}
+
+func Test_DeleteQueryBuilder_SoftDeleteAudit(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	builder := NewDeleteQueryBuilder().
+		SoftDelete("deleted_at").
+		Audit(&AuditColumns{
+			CreatedAt: "created_at",
+			UpdatedAt: "updated_at",
+			CreatedBy: "created_by",
+			UpdatedBy: "updated_by",
+			Now:       func() time.Time { return now },
+		}).
+		Context(ContextWithActor(context.Background(), "admin")).
+		Table("users").
+		Where("user_id", Equal)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET deleted_at = ?, updated_at = ?, updated_by = ? WHERE user_id = ? AND deleted_at IS NULL;",
+		builder.Build(),
+		true,
+	)
+
+	args, err := builder.Args(map[string]interface{}{"user_id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{now, now, "admin", 1}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND deleted_at IS NULL;",
+		NewDeleteQueryBuilder().
+			SoftDelete("deleted_at").
+			Audit(&AuditColumns{UpdatedAt: "updated_at"}).
+			Table("users").
+			Where("user_id", Equal).
+			Build(),
+		true,
+	)
+
+	// audit columns are not set by hard delete
+	testCommonFunc(
+		t,
+		"DELETE FROM users WHERE user_id = ?;",
+		builder.HardDelete().Build(),
+		true,
+	)
+}
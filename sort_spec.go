@@ -0,0 +1,49 @@
+package query_builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+var InvalidSortSpecErr = fmt.Errorf("sort spec is invalid")
+
+type UnknownSortFieldError struct {
+	Field string
+}
+
+func (err *UnknownSortFieldError) Error() string {
+	return fmt.Sprintf("sort field %q is not allowed", err.Field)
+}
+
+type SortSpec struct {
+	Field string
+	Order string
+}
+
+// ex. "-created_at,name" => [{created_at DESC} {name ASC}]
+// "-" prefix is DESC, "+" prefix or no prefix is ASC. repeated field is invalid. ex. "-created,created"
+func ParseSortSpec(input string) ([]SortSpec, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	keys := strings.Split(input, ",")
+	specs := make([]SortSpec, 0, len(keys))
+	fields := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		spec := SortSpec{Field: key, Order: Asc}
+		if strings.HasPrefix(key, "-") {
+			spec = SortSpec{Field: key[1:], Order: Desc}
+		} else if strings.HasPrefix(key, "+") {
+			spec.Field = key[1:]
+		}
+
+		if spec.Field == "" || fields[spec.Field] {
+			return nil, fmt.Errorf("%w: %q", InvalidSortSpecErr, input)
+		}
+		fields[spec.Field] = true
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
@@ -0,0 +1,38 @@
+package query_builder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_ParseSortSpec(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []SortSpec
+		err      error
+	}{
+		{"", nil, nil},
+		{"name", []SortSpec{{"name", Asc}}, nil},
+		{"-created_at,name", []SortSpec{{"created_at", Desc}, {"name", Asc}}, nil},
+		{" +name , -age ", []SortSpec{{"name", Asc}, {"age", Desc}}, nil},
+		{"name,,age", nil, InvalidSortSpecErr},
+		{"-", nil, InvalidSortSpecErr},
+		{"-created,created", nil, InvalidSortSpecErr},
+		{"name,+name", nil, InvalidSortSpecErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, err := ParseSortSpec(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Logf("expected: %v, actual: %v", tt.err, err)
+				t.Fail()
+			}
+			if !reflect.DeepEqual(tt.expected, actual) {
+				t.Logf("expected: %v, actual: %v", tt.expected, actual)
+				t.Fail()
+			}
+		})
+	}
+}
@@ -1,7 +1,8 @@
 package query_builder
 
 import (
-	"fmt"
+	"context"
+	"database/sql"
 	"strings"
 )
 
@@ -28,6 +29,97 @@ func (builder *UpdateQueryBuilder) Placeholder(placeholderType int) *UpdateQuery
 	return copied
 }
 
+// Default dialect is MySQL. dialect decides quote characters.
+func (builder *UpdateQueryBuilder) Dialect(dialect int) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setDialect(dialect)
+	return copied
+}
+
+// Default is QuoteNever. QuoteWhenNeeded quotes reserved words and identifiers including special characters.
+func (builder *UpdateQueryBuilder) Quote(quoteMode int) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.quote(quoteMode)
+	return copied
+}
+
+// Strict mode panics on Build when identifiers are not safe. expressions need to be wrapped by Raw.
+func (builder *UpdateQueryBuilder) Strict() *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setStrict()
+	return copied
+}
+
+// WhereInValues renders = ANY(bind) and WhereNotInValues renders <> ALL(bind) with slice value. PostgreSQL only
+func (builder *UpdateQueryBuilder) ArrayBinding() *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setArrayBinding()
+	return copied
+}
+
+// overrides columns of SetDefaultAuditColumns. &AuditColumns{} disables.
+func (builder *UpdateQueryBuilder) Audit(columns *AuditColumns) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setAudit(columns)
+	return copied
+}
+
+// columns of SetDefaultAuditColumns are filled. builders without DefaultAudit or Audit have no audit columns
+func (builder *UpdateQueryBuilder) DefaultAudit() *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setDefaultAudit()
+	return copied
+}
+
+// actor of ContextWithActor is bound to audit columns
+func (builder *UpdateQueryBuilder) Context(ctx context.Context) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setContext(ctx)
+	return copied
+}
+
+// SET {column} = {column} + 1 and WHERE {column} = ? are added. same as `db:"version,version"` of Model
+func (builder *UpdateQueryBuilder) OptimisticLock(column string) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.optimisticLock(column)
+	return copied
+}
+
+// soft delete column of this builder. overrides SetSoftDelete. ex. SoftDelete("deleted_at")
+func (builder *UpdateQueryBuilder) SoftDelete(column string) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.softDelete(column)
+	return copied
+}
+
+// soft deleted rows are not excluded
+func (builder *UpdateQueryBuilder) WithTrashed() *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setTrashed(withTrashed)
+	return copied
+}
+
+// only soft deleted rows. ex. WHERE deleted_at IS NOT NULL
+func (builder *UpdateQueryBuilder) OnlyTrashed() *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.setTrashed(onlyTrashed)
+	return copied
+}
+
+// scopes are added to WHERE with table on Build. ex. Scope(ActiveScope)
+func (builder *UpdateQueryBuilder) Scope(scopes ...Scope) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.scope(scopes...)
+	return copied
+}
+
+// removes mandatory scopes of SetMandatoryScope by name, all scopes without names. ex. Unscoped("tenant")
+func (builder *UpdateQueryBuilder) Unscoped(names ...string) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.unscope(names...)
+	return copied
+}
+
 func (builder *UpdateQueryBuilder) Table(tableName string) *UpdateQueryBuilder {
 	copied := builder.copy()
 	copied.queryBuilder = builder.table(tableName)
@@ -36,7 +128,7 @@ func (builder *UpdateQueryBuilder) Table(tableName string) *UpdateQueryBuilder {
 
 func (builder *UpdateQueryBuilder) Model(src interface{}, notIgnoreZeroValue ...bool) *UpdateQueryBuilder {
 	copied := builder.copy()
-	copied.queryBuilder = builder.model(src, notIgnoreZeroValue...)
+	copied.queryBuilder = builder.model(src, modelUpdate, nil, notIgnoreZeroValue...)
 	return copied
 }
 
@@ -46,6 +138,14 @@ func (builder *UpdateQueryBuilder) Column(columns ...string) *UpdateQueryBuilder
 	return copied
 }
 
+// ex. SetCase("rank", Case().When(Cond("score", GraterThanEqual), "'gold'").Else("'silver'"))
+// => SET rank = CASE WHEN score >= ? THEN 'gold' ELSE 'silver' END
+func (builder *UpdateQueryBuilder) SetCase(column string, expression *CaseExpression) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.caseColumn(column, expression)
+	return copied
+}
+
 func (builder *UpdateQueryBuilder) Omit(columns ...string) *UpdateQueryBuilder {
 	copied := builder.copy()
 	copied.queryBuilder = builder.omit(columns...)
@@ -58,6 +158,38 @@ func (builder *UpdateQueryBuilder) Where(column, operator string, bind ...string
 	return copied
 }
 
+// ex. WhereRaw(Raw("LOWER(email)"), Equal, "email") => WHERE LOWER(email) = ?
+func (builder *UpdateQueryBuilder) WhereRaw(expression RawExpr, operator string, bind ...string) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereRaw("AND", expression, operator, bind...)
+	return copied
+}
+
+// ex. WhereCondition(AnyOf(Cond("name", Like), Cond("email", Like))) => WHERE (name LIKE ? OR email LIKE ?)
+func (builder *UpdateQueryBuilder) WhereCondition(condition *Condition) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereCondition("AND", condition)
+	return copied
+}
+
+func (builder *UpdateQueryBuilder) OrCondition(condition *Condition) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereCondition("OR", condition)
+	return copied
+}
+
+func (builder *UpdateQueryBuilder) WhereInValues(column string, values interface{}, bind ...string) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereInValues(In, column, values, bind...)
+	return copied
+}
+
+func (builder *UpdateQueryBuilder) WhereNotInValues(column string, values interface{}, bind ...string) *UpdateQueryBuilder {
+	copied := builder.copy()
+	copied.queryBuilder = builder.whereInValues(NotIn, column, values, bind...)
+	return copied
+}
+
 func (builder *UpdateQueryBuilder) WhereIn(column string, listLength int, bind ...string) *UpdateQueryBuilder {
 	copied := builder.copy()
 	copied.queryBuilder = builder.whereIn(column, listLength, bind...)
@@ -70,11 +202,68 @@ func (builder *UpdateQueryBuilder) WhereNotIn(column string, listLength int, bin
 	return copied
 }
 
+// returns error when identifiers are not safe. Strict mode uses this on Build.
+func (builder *UpdateQueryBuilder) Validate() error {
+	return builder.validate()
+}
+
+// args in placeholder order for Question and DollarNumber. values of WhereInValues and audit are merged with namedArgs.
+// query is built as Named and compiled, so binds need to be unique.
+func (builder *UpdateQueryBuilder) Args(namedArgs ...map[string]interface{}) ([]interface{}, error) {
+	builder = &UpdateQueryBuilder{builder.freezeClock()}
+	_, args, err := Compile(builder.Placeholder(Named).Build(), builder.audited(modelUpdate).getNamedArgs(namedArgs), Question)
+	return args, err
+}
+
+// executes query with arg(struct or map) and values of builder.
+// returns *ConflictError when optimistic lock is used and no row is affected.
+func (builder *UpdateQueryBuilder) ExecContext(ctx context.Context, execer Execer, arg interface{}) (sql.Result, error) {
+	values, err := getNamedValues(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	builder = &UpdateQueryBuilder{builder.freezeClock()}
+	placeholderType := builder.placeholderType
+	if placeholderType == Named {
+		placeholderType = Question
+	}
+
+	values = builder.audited(modelUpdate).getNamedArgs([]map[string]interface{}{values})
+	query, args, err := Compile(builder.Placeholder(Named).Build(), values, placeholderType)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := execer.ExecContext(ctx, query, args...)
+	if err != nil || builder.versionColumn == "" {
+		return result, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return result, err
+	}
+	if affected == 0 {
+		return result, &ConflictError{Table: builder.tableName, Version: values[builder.versionColumn]}
+	}
+	return result, nil
+}
+
 func (builder *UpdateQueryBuilder) Build() string {
+	// placeholders are numbered on the copy, so Build can be called repeatedly
+	builder = &UpdateQueryBuilder{builder.copy().audited(modelUpdate).versioned().softDeleteScope(false).scoped()}
+
 	if builder.tableName == "" {
 		panic("target table is empty!!!")
 	}
 
+	if builder.strict {
+		if err := builder.Validate(); err != nil {
+			panic(err)
+		}
+	}
+
 	if len(builder.columns) == 0 {
 		panic("target columns is empty!!!")
 	}
@@ -82,7 +271,7 @@ func (builder *UpdateQueryBuilder) Build() string {
 	copied := builder.copy()
 	columns := builder.columns
 
-	copied.query = append(copied.query, "UPDATE", builder.tableName)
+	copied.query = append(copied.query, "UPDATE", builder.quoteIdentifier(builder.tableName))
 	copied.query = append(copied.query, builder.getSetParagraphs(columns...))
 
 	if len(builder.whereConditions) > 0 {
@@ -91,16 +280,3 @@ func (builder *UpdateQueryBuilder) Build() string {
 
 	return strings.TrimRight(strings.Join(copied.query, " "), "") + ";"
 }
-
-func (builder *UpdateQueryBuilder) getSetParagraphs(columns ...string) string {
-	setContents := make([]string, 0, len(columns))
-	bind := "?"
-	format := "%s = %s"
-	for _, column := range columns {
-		if builder.placeholderType == Named {
-			bind = ":" + column
-		}
-		setContents = append(setContents, fmt.Sprintf(format, column, bind))
-	}
-	return fmt.Sprintf("SET %s", strings.Join(setContents, ", "))
-}
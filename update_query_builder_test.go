@@ -1,7 +1,10 @@
 package query_builder
 
 import (
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_UpdateQueryBuilder_Model(t *testing.T) {
@@ -116,6 +119,55 @@ func Test_UpdateQueryBuilder_Where(t *testing.T) {
 	)
 }
 
+// CASE result can not add another SET assignment in strict mode
+func Test_UpdateQueryBuilder_StrictSetCase(t *testing.T) {
+	builder := NewUpdateQueryBuilder().
+		Strict().
+		Table("users").
+		SetCase("role", Case().When(Cond("a", Equal), "'admin' END, password = ('x'").Else("role"))
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, InvalidIdentifierErr) {
+			t.Logf("expected: %v, actual: %v", InvalidIdentifierErr, err)
+			t.Fail()
+		}
+	}()
+	_ = builder.Build()
+}
+
+func Test_UpdateQueryBuilder_WhereRaw(t *testing.T) {
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ? WHERE LOWER(email) = ?;",
+		NewUpdateQueryBuilder().
+			Strict().
+			Table("users").
+			Column("name").
+			WhereRaw(Raw("LOWER(email)"), Equal, "email").
+			Build(),
+		true,
+	)
+}
+
+// SET is numbered with WHERE, and numbering restarts on each Build
+func Test_UpdateQueryBuilder_DollarNumber(t *testing.T) {
+	builder := NewUpdateQueryBuilder().
+		Placeholder(DollarNumber).
+		Table("users").
+		Column("name", "age").
+		Where("user_id", Equal)
+
+	for i := 0; i < 2; i++ {
+		testCommonFunc(
+			t,
+			"UPDATE users SET name = $1, age = $2 WHERE user_id = $3;",
+			builder.Build(),
+			false,
+		)
+	}
+}
+
 func Test_UpdateQueryBuilder_WhereIn(t *testing.T) {
 	q := NewUpdateQueryBuilder().
 		Table("users").
@@ -186,3 +238,190 @@ func Test_UpdateQueryBuilder_WhereNotIn(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func Test_UpdateQueryBuilder_SetCase(t *testing.T) {
+	rank := Case().
+		When(Cond("score", GraterThanEqual, "gold_score"), "'gold'").
+		When(Cond("score", GraterThanEqual, "silver_score").And("age", LessThan), "'silver'").
+		Else("rank")
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ?, rank = CASE WHEN score >= ? THEN 'gold' WHEN score >= ? AND age < ? THEN 'silver' ELSE rank END WHERE user_id = ?;",
+		NewUpdateQueryBuilder().
+			Table("users").
+			Column("name").
+			SetCase("rank", rank).
+			Where("user_id", Equal).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = $1, rank = CASE WHEN score >= $2 THEN 'gold' WHEN score >= $3 AND age < $4 THEN 'silver' ELSE rank END WHERE user_id = $5;",
+		NewUpdateQueryBuilder().
+			Placeholder(DollarNumber).
+			Table("users").
+			Column("name").
+			SetCase("rank", rank).
+			Where("user_id", Equal).
+			Build(),
+		false,
+	)
+
+	// CASE follows its column when columns before it are omitted
+	testCommonFunc(
+		t,
+		"UPDATE users SET rank = CASE WHEN score >= ? THEN 'gold' WHEN score >= ? AND age < ? THEN 'silver' ELSE rank END, name = ? WHERE user_id = ?;",
+		NewUpdateQueryBuilder().
+			Table("users").
+			Column("age").
+			SetCase("rank", rank).
+			Column("name").
+			Omit("age").
+			Where("user_id", Equal).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET rank = CASE WHEN score >= :gold_score THEN 'gold' WHEN score >= :silver_score AND age < :age THEN 'silver' ELSE rank END;",
+		NewUpdateQueryBuilder().
+			Placeholder(Named).
+			Table("users").
+			SetCase("rank", rank).
+			Build(),
+		true,
+	)
+}
+
+func Test_UpdateQueryBuilder_Quote(t *testing.T) {
+	testCommonFunc(
+		t,
+		"UPDATE [users] SET [name] = ?, [order] = ? WHERE [user_id] = ?;",
+		NewUpdateQueryBuilder().
+			Dialect(SQLServer).
+			Quote(QuoteAlways).
+			Table("users").
+			Column("name", "order").
+			Where("user_id", Equal).
+			Build(),
+		false,
+	)
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET name = ?, `order` = ? WHERE user_id = ?;",
+		NewUpdateQueryBuilder().
+			Quote(QuoteWhenNeeded).
+			Table("users").
+			Column("name", "order").
+			Where("user_id", Equal).
+			Build(),
+		true,
+	)
+	testCommonFunc(
+		t,
+		"UPDATE `users` SET `first name` = ? WHERE `user_id` = ?;",
+		NewUpdateQueryBuilder().
+			Quote(QuoteAlways).
+			Table("users").
+			Column("first name").
+			Where("user_id", Equal).
+			Build(),
+		false,
+	)
+}
+
+func Test_UpdateQueryBuilder_WhereInValues(t *testing.T) {
+	builder := NewUpdateQueryBuilder().
+		Table("users").
+		Column("status").
+		WhereInValues("user_id", []string{"u1", "u2"})
+
+	testCommonFunc(
+		t,
+		"UPDATE users SET status = ? WHERE user_id IN (?, ?);",
+		builder.Build(),
+		false,
+	)
+
+	args, err := builder.Args(map[string]interface{}{"status": "inactive"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArgs := []interface{}{"inactive", "u1", "u2"}
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Logf("expected: %v, actual: %v", expectedArgs, args)
+		t.Fail()
+	}
+}
+
+func Test_UpdateQueryBuilder_ModelTagOption(t *testing.T) {
+	type Article struct {
+		ID        int       `db:"id,pk,autoincr" table:"articles"`
+		Title     string    `db:"title" table:"articles"`
+		Nickname  string    `db:"nickname,omitempty" table:"articles"`
+		Status    string    `db:"status,default" table:"articles"`
+		CreatedAt time.Time `db:"created_at,readonly" table:"articles"`
+		Memo      string    `db:"-" table:"articles"`
+		Active    bool      `db:"active,always" table:"articles"`
+	}
+
+	testCommonFunc(
+		t,
+		"UPDATE articles SET title = :title, status = :status, active = :active WHERE id = :id;",
+		NewUpdateQueryBuilder().
+			Placeholder(Named).
+			Table("articles").
+			Model(Article{ID: 1}, true).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"INSERT INTO articles(title, nickname, active) VALUES(?, ?, ?);",
+		NewInsertQueryBuilder().
+			Table("articles").
+			Model(Article{ID: 1, Title: "title", Nickname: "tre", Memo: "memo"}, true).
+			Build(),
+		true,
+	)
+
+	testCommonFunc(
+		t,
+		"SELECT articles.id, articles.title, articles.nickname, articles.status, articles.created_at, articles.active FROM articles;",
+		NewSelectQueryBuilder().
+			Table("articles").
+			Model(Article{}).
+			Build(),
+		true,
+	)
+
+	// always writes zero value without notIgnoreZeroValue
+	testCommonFunc(
+		t,
+		"UPDATE articles SET title = ?, active = ? WHERE id = ?;",
+		NewUpdateQueryBuilder().
+			Table("articles").
+			Model(Article{ID: 1, Title: "title"}).
+			Build(),
+		true,
+	)
+
+	// condition of primary key is added once
+	testCommonFunc(
+		t,
+		"UPDATE articles SET title = ?, active = ? WHERE id = ?;",
+		NewUpdateQueryBuilder().
+			Table("articles").
+			Where("id", Equal).
+			Model(Article{ID: 1, Title: "title"}).
+			Model(Article{ID: 2}).
+			Build(),
+		true,
+	)
+}
@@ -0,0 +1,138 @@
+package query_builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Window struct {
+	function    string
+	functionRaw bool
+	name        string
+	partitionBy []string
+	orderBy     []map[string]string
+	frame       string
+	frameBounds []string
+	frameRaw    bool
+}
+
+// ex. Over("ROW_NUMBER()").PartitionBy("user_id").OrderBy("created", Desc)
+// => ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created DESC)
+// Strict mode accepts only identifier or literal function, function call needs OverRaw.
+func Over(function string) *Window {
+	return &Window{function: function}
+}
+
+// ex. OverRaw(Raw("SUM(orders.total)")).Name("w") => SUM(orders.total) OVER w
+func OverRaw(function RawExpr) *Window {
+	return &Window{function: function.expression, functionRaw: true}
+}
+
+// window definition for SelectQueryBuilder.Window
+func NewWindow() *Window {
+	return &Window{}
+}
+
+func (window *Window) copy() *Window {
+	return &Window{
+		function:    window.function,
+		functionRaw: window.functionRaw,
+		name:        window.name,
+		partitionBy: window.partitionBy,
+		orderBy:     window.orderBy,
+		frame:       window.frame,
+		frameBounds: window.frameBounds,
+		frameRaw:    window.frameRaw,
+	}
+}
+
+// refer named window defined by SelectQueryBuilder.Window
+func (window *Window) Name(name string) *Window {
+	copied := window.copy()
+	copied.name = name
+	return copied
+}
+
+func (window *Window) PartitionBy(columns ...string) *Window {
+	copied := window.copy()
+	copied.partitionBy = append(append(make([]string, 0, len(window.partitionBy)+len(columns)), window.partitionBy...), columns...)
+	return copied
+}
+
+func (window *Window) OrderBy(column, order string) *Window {
+	copied := window.copy()
+	copied.orderBy = append(append(make([]map[string]string, 0, len(window.orderBy)+1), window.orderBy...), map[string]string{
+		"column": column,
+		"order":  order,
+	})
+	return copied
+}
+
+// ex. Rows(UnboundedPreceding, CurrentRow) => ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+// Strict mode accepts only frame constants and {number} PRECEDING | FOLLOWING, other bounds need RowsRaw and RangeRaw.
+func (window *Window) Rows(start, end string) *Window {
+	return window.withFrame("ROWS", start, end, false)
+}
+
+func (window *Window) Range(start, end string) *Window {
+	return window.withFrame("RANGE", start, end, false)
+}
+
+// ex. RangeRaw(Raw("INTERVAL '1' DAY PRECEDING"), Raw(CurrentRow))
+func (window *Window) RowsRaw(start, end RawExpr) *Window {
+	return window.withFrame("ROWS", start.expression, end.expression, true)
+}
+
+func (window *Window) RangeRaw(start, end RawExpr) *Window {
+	return window.withFrame("RANGE", start.expression, end.expression, true)
+}
+
+func (window *Window) withFrame(unit, start, end string, raw bool) *Window {
+	copied := window.copy()
+	copied.frame = fmt.Sprintf("%s BETWEEN %s AND %s", unit, start, end)
+	copied.frameBounds = []string{start, end}
+	copied.frameRaw = raw
+	return copied
+}
+
+// identifiers are not quoted. builder renders window with its quote mode
+func (window *Window) String() string {
+	return window.build(newQueryBuilder())
+}
+
+// function and frame are rendered as it is, name and columns are quoted by builder
+func (window *Window) build(builder *queryBuilder) string {
+	spec := window.getSpecification(builder)
+	if window.function == "" {
+		return fmt.Sprintf("(%s)", spec)
+	}
+	if window.name != "" && spec == builder.quoteIdentifier(window.name) {
+		return fmt.Sprintf("%s OVER %s", window.function, spec)
+	}
+	return fmt.Sprintf("%s OVER (%s)", window.function, spec)
+}
+
+func (window *Window) getSpecification(builder *queryBuilder) string {
+	paragraphs := make([]string, 0, 4)
+	if window.name != "" {
+		paragraphs = append(paragraphs, builder.quoteIdentifier(window.name))
+	}
+	if len(window.partitionBy) > 0 {
+		partitionBy := make([]string, 0, len(window.partitionBy))
+		for _, column := range window.partitionBy {
+			partitionBy = append(partitionBy, builder.quoteIdentifier(column))
+		}
+		paragraphs = append(paragraphs, "PARTITION BY "+strings.Join(partitionBy, ", "))
+	}
+	if len(window.orderBy) > 0 {
+		orderBy := make([]string, 0, len(window.orderBy))
+		for _, order := range window.orderBy {
+			orderBy = append(orderBy, builder.quoteIdentifier(order["column"])+" "+order["order"])
+		}
+		paragraphs = append(paragraphs, "ORDER BY "+strings.Join(orderBy, ", "))
+	}
+	if window.frame != "" {
+		paragraphs = append(paragraphs, window.frame)
+	}
+	return strings.Join(paragraphs, " ")
+}
@@ -0,0 +1,61 @@
+package query_builder
+
+import "testing"
+
+func Test_Window_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   *Window
+		expected string
+	}{
+		{
+			name:     "empty over",
+			window:   Over("COUNT(*)"),
+			expected: "COUNT(*) OVER ()",
+		},
+		{
+			name:     "partition and order",
+			window:   Over("ROW_NUMBER()").PartitionBy("user_id").OrderBy("created", Desc),
+			expected: "ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created DESC)",
+		},
+		{
+			name:     "frame",
+			window:   Over("SUM(orders.total)").PartitionBy("orders.user_id").OrderBy("orders.created", Asc).Rows(UnboundedPreceding, CurrentRow),
+			expected: "SUM(orders.total) OVER (PARTITION BY orders.user_id ORDER BY orders.created ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)",
+		},
+		{
+			name:     "named window",
+			window:   Over("RANK()").Name("w"),
+			expected: "RANK() OVER w",
+		},
+		{
+			name:     "named window with additional order",
+			window:   Over("RANK()").Name("w").OrderBy("score", Desc),
+			expected: "RANK() OVER (w ORDER BY score DESC)",
+		},
+		{
+			name:     "definition",
+			window:   NewWindow().PartitionBy("user_id", "team_id").Range("1 PRECEDING", UnboundedFollowing),
+			expected: "(PARTITION BY user_id, team_id RANGE BETWEEN 1 PRECEDING AND UNBOUNDED FOLLOWING)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkQuery(tt.expected, tt.window.String()); err != nil {
+				t.Log(err)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func Test_Window_IsImmutable(t *testing.T) {
+	base := Over("ROW_NUMBER()").PartitionBy("user_id")
+	_ = base.PartitionBy("team_id").OrderBy("created", Asc)
+
+	if err := checkQuery("ROW_NUMBER() OVER (PARTITION BY user_id)", base.String()); err != nil {
+		t.Log(err)
+		t.Fail()
+	}
+}